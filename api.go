@@ -12,9 +12,21 @@ import (
 
 const bearerToken string = "AAAAAAAAAAAAAAAAAAAAAPYXBAAAAAAACLXUNDekMxqa8h%2F40K4moUkGsoc%3DTYfbDKbT3jJPCEVnMYqilB28NHfOPqkca3qaAxGfsyKCs0wRbw"
 
+// StatusError is the error of a response with a status other than 200 OK.
+type StatusError struct {
+	StatusCode int
+	// Status is the status line, like "429 Too Many Requests".
+	Status string
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("response status %s: %s", e.Status, e.Body)
+}
+
 // RequestAPI get JSON from frontend API and decodes it
 func (s *Scraper) RequestAPI(req *http.Request, target interface{}) error {
-	s.wg.Wait()
+	s.waitDelay()
 	if s.delay > 0 {
 		defer s.delayRequest()
 	}
@@ -33,12 +45,30 @@ func (s *Scraper) RequestAPI(req *http.Request, target interface{}) error {
 	return s.handleResponse(resp, target)
 }
 
+// waitDelay waits for the delay set with WithDelay to pass since the
+// previous request, and holds the next slot so concurrent requests are
+// spaced by the delay too.
+func (s *Scraper) waitDelay() {
+	if s.delay <= 0 {
+		return
+	}
+	s.mu.Lock()
+	start := s.nextRequestAt
+	if now := time.Now(); start.Before(now) {
+		start = now
+	}
+	s.nextRequestAt = start.Add(time.Second * time.Duration(s.delay))
+	s.mu.Unlock()
+	time.Sleep(time.Until(start))
+}
+
+// delayRequest starts the delay once a request is done.
 func (s *Scraper) delayRequest() {
-	s.wg.Add(1)
-	go func() {
-		time.Sleep(time.Second * time.Duration(s.delay))
-		s.wg.Done()
-	}()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next := time.Now().Add(time.Second * time.Duration(s.delay)); next.After(s.nextRequestAt) {
+		s.nextRequestAt = next
+	}
 }
 
 func (s *Scraper) prepareRequest(req *http.Request) error {
@@ -66,21 +96,35 @@ func (s *Scraper) prepareRequest(req *http.Request) error {
 }
 
 func (s *Scraper) setGuestToken(req *http.Request) error {
-	if !s.IsGuestToken() || s.guestCreatedAt.Before(time.Now().Add(-time.Hour*3)) {
-		if err := s.GetGuestToken(); err != nil {
+	token, err := s.currentGuestToken()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Guest-Token", token)
+	return nil
+}
+
+// currentGuestToken returns the guest token, and gets a new one when it is
+// missing or older than three hours. Concurrent callers share the same one.
+func (s *Scraper) currentGuestToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.guestToken == "" || s.guestCreatedAt.Before(time.Now().Add(-time.Hour*3)) {
+		if err := s.getGuestToken(); err != nil {
 			logrus.WithError(err).Error("Failed to get guest token")
-			return err
+			return "", err
 		}
 	}
-	req.Header.Set("X-Guest-Token", s.guestToken)
-	return nil
+	return s.guestToken, nil
 }
 
 func (s *Scraper) setAuthorizationHeader(req *http.Request) {
 	if s.oAuthToken != "" && s.oAuthSecret != "" {
 		req.Header.Set("Authorization", s.sign(req.Method, req.URL))
 	} else {
+		s.mu.Lock()
 		req.Header.Set("Authorization", "Bearer "+s.bearerToken)
+		s.mu.Unlock()
 	}
 }
 
@@ -105,11 +149,13 @@ func (s *Scraper) handleResponse(resp *http.Response, target interface{}) error
 			"status":  resp.Status,
 			"content": string(content),
 		}).Error("Unexpected response status")
-		return fmt.Errorf("response status %s: %s", resp.Status, content)
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(content)}
 	}
 
 	if resp.Header.Get("X-Rate-Limit-Remaining") == "0" {
+		s.mu.Lock()
 		s.guestToken = ""
+		s.mu.Unlock()
 	}
 
 	if target == nil {
@@ -120,6 +166,13 @@ func (s *Scraper) handleResponse(resp *http.Response, target interface{}) error
 
 // GetGuestToken from Twitter API
 func (s *Scraper) GetGuestToken() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.getGuestToken()
+}
+
+// getGuestToken is GetGuestToken for callers holding s.mu.
+func (s *Scraper) getGuestToken() error {
 	req, err := http.NewRequest("POST", "https://api.twitter.com/1.1/guest/activate.json", nil)
 	if err != nil {
 		return err
@@ -140,7 +193,7 @@ func (s *Scraper) GetGuestToken() error {
 		return err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("response status %s: %s", resp.Status, body)
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body)}
 	}
 
 	var jsn map[string]interface{}
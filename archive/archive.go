@@ -0,0 +1,426 @@
+// Package archive stores scraped tweets and profiles in an embedded SQLite
+// database, deduplicating them by ID.
+//
+// Saving a tweet again updates it in place and records its engagement
+// counters (likes, retweets, replies and views) in a history table whenever
+// they changed. Authors, media, mentions, hashtags and the reply, quote,
+// retweet and thread edges between tweets are stored in their own tables so
+// that they can be queried.
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+
+	// Pure-Go SQLite driver, registered as "sqlite".
+	_ "modernc.org/sqlite"
+)
+
+// Edge kinds stored in the edges table.
+const (
+	EdgeReply   = "reply"
+	EdgeQuote   = "quote"
+	EdgeRetweet = "retweet"
+	EdgeThread  = "thread"
+)
+
+// Archive of tweets and profiles.
+type Archive struct {
+	db *sql.DB
+}
+
+// Engagement counters of a tweet observed at a point in time.
+type Engagement struct {
+	ObservedAt time.Time
+	Likes      int
+	Retweets   int
+	Replies    int
+	Views      int
+}
+
+// Open opens or creates the archive at path. Use ":memory:" for a temporary
+// in-memory archive. It fails on archives created with another schema
+// version.
+func Open(path string) (*Archive, error) {
+	dsn := path
+	if path != ":memory:" {
+		dsn = "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serializes writes; a single connection also keeps in-memory
+	// databases alive and shared.
+	db.SetMaxOpenConns(1)
+
+	if err := createSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Archive{db: db}, nil
+}
+
+// createSchema creates the tables of a new archive, and checks the schema
+// version of an existing one. Version 0 is a new database.
+func createSchema(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("archive: read schema version: %w", err)
+	}
+	if version != 0 && version != schemaVersion {
+		return fmt.Errorf("archive: schema version %d is not supported, want %d", version, schemaVersion)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("archive: create schema: %w", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close the archive.
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
+
+// DB returns the underlying database for custom queries.
+func (a *Archive) DB() *sql.DB {
+	return a.db
+}
+
+// SaveTweet upserts a tweet along with the tweets it references
+// (InReplyToStatus, QuotedStatus, RetweetedStatus and Thread).
+func (a *Archive) SaveTweet(ctx context.Context, tweet *twitterscraper.Tweet) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := saveTweet(ctx, tx, tweet, time.Now(), make(map[string]bool)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveTweets upserts every tweet received from tweets and returns the number
+// of tweets saved. It stops at the first result error.
+func (a *Archive) SaveTweets(ctx context.Context, tweets <-chan *twitterscraper.TweetResult) (int, error) {
+	n := 0
+	for tweet := range tweets {
+		if tweet.Error != nil {
+			return n, tweet.Error
+		}
+		if err := a.SaveTweet(ctx, &tweet.Tweet); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// SaveProfile upserts a user profile. Username changes are kept in the
+// user_names table.
+func (a *Archive) SaveProfile(ctx context.Context, profile *twitterscraper.Profile) error {
+	if profile.UserID == "" {
+		return fmt.Errorf("archive: profile %q has no user ID", profile.Username)
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := upsertUser(ctx, tx, profile.UserID, profile.Username, profile.Name, string(data), time.Now()); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveTweet(ctx context.Context, tx *sql.Tx, tweet *twitterscraper.Tweet, now time.Time, seen map[string]bool) error {
+	if tweet == nil || tweet.ID == "" || seen[tweet.ID] {
+		return nil
+	}
+	seen[tweet.ID] = true
+
+	data, err := json.Marshal(tweet)
+	if err != nil {
+		return err
+	}
+	if tweet.UserID != "" && tweet.Username != "" {
+		if err := upsertUser(ctx, tx, tweet.UserID, tweet.Username, tweet.Name, "", now); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tweets (id, conversation_id, user_id, created_at, text, likes, retweets, replies, views, data, first_seen_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			conversation_id = excluded.conversation_id,
+			user_id = excluded.user_id,
+			created_at = excluded.created_at,
+			text = excluded.text,
+			likes = excluded.likes,
+			retweets = excluded.retweets,
+			replies = excluded.replies,
+			views = excluded.views,
+			data = excluded.data,
+			updated_at = excluded.updated_at`,
+		tweet.ID, tweet.ConversationID, tweet.UserID, tweet.Timestamp, tweet.Text,
+		tweet.Likes, tweet.Retweets, tweet.Replies, tweet.Views, string(data), now.Unix(), now.Unix())
+	if err != nil {
+		return err
+	}
+
+	if err := recordEngagement(ctx, tx, tweet, now); err != nil {
+		return err
+	}
+	if err := replaceEntities(ctx, tx, tweet); err != nil {
+		return err
+	}
+
+	refs := []*twitterscraper.Tweet{tweet.InReplyToStatus, tweet.QuotedStatus, tweet.RetweetedStatus}
+	refs = append(refs, tweet.Thread...)
+	for _, ref := range refs {
+		// Skip ID-only references, e.g. thread entries decoded from JSON
+		// without embedding, so they do not overwrite archived tweets.
+		if ref == nil || ref.UserID == "" {
+			continue
+		}
+		if err := saveTweet(ctx, tx, ref, now, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordEngagement appends the tweet counters to the history unless they
+// are unchanged since the last observation.
+func recordEngagement(ctx context.Context, tx *sql.Tx, tweet *twitterscraper.Tweet, now time.Time) error {
+	var likes, retweets, replies, views int
+	err := tx.QueryRowContext(ctx, `
+		SELECT likes, retweets, replies, views FROM engagement_history
+		WHERE tweet_id = ? ORDER BY observed_at DESC, rowid DESC LIMIT 1`, tweet.ID).
+		Scan(&likes, &retweets, &replies, &views)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && likes == tweet.Likes && retweets == tweet.Retweets && replies == tweet.Replies && views == tweet.Views {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO engagement_history (tweet_id, observed_at, likes, retweets, replies, views)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		tweet.ID, now.UnixMilli(), tweet.Likes, tweet.Retweets, tweet.Replies, tweet.Views)
+	return err
+}
+
+func replaceEntities(ctx context.Context, tx *sql.Tx, tweet *twitterscraper.Tweet) error {
+	for _, table := range []string{"media", "mentions", "hashtags", "edges"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE tweet_id = ?", tweet.ID); err != nil {
+			return err
+		}
+	}
+
+	insertMedia := func(id, typ, url, preview string) error {
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO media (id, tweet_id, type, url, preview) VALUES (?, ?, ?, ?, ?)`,
+			id, tweet.ID, typ, url, preview)
+		return err
+	}
+	for _, photo := range tweet.Photos {
+		if err := insertMedia(photo.ID, "photo", photo.URL, ""); err != nil {
+			return err
+		}
+	}
+	for _, video := range tweet.Videos {
+		if err := insertMedia(video.ID, "video", video.URL, video.Preview); err != nil {
+			return err
+		}
+	}
+	for _, gif := range tweet.GIFs {
+		if err := insertMedia(gif.ID, "animated_gif", gif.URL, gif.Preview); err != nil {
+			return err
+		}
+	}
+
+	for _, mention := range tweet.Mentions {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO mentions (tweet_id, user_id, username) VALUES (?, ?, ?)`,
+			tweet.ID, mention.ID, mention.Username); err != nil {
+			return err
+		}
+	}
+	for _, tag := range tweet.Hashtags {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO hashtags (tweet_id, tag) VALUES (?, ?)`,
+			tweet.ID, tag); err != nil {
+			return err
+		}
+	}
+
+	edges := [][2]string{
+		{EdgeReply, tweet.InReplyToStatusID},
+		{EdgeQuote, tweet.QuotedStatusID},
+		{EdgeRetweet, tweet.RetweetedStatusID},
+	}
+	for _, t := range tweet.Thread {
+		edges = append(edges, [2]string{EdgeThread, t.ID})
+	}
+	for _, edge := range edges {
+		if edge[1] == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO edges (tweet_id, kind, target_id) VALUES (?, ?, ?)`,
+			tweet.ID, edge[0], edge[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertUser inserts or updates a user. Profile data is only replaced when
+// data is not empty, so authors seen on tweets do not erase full profiles.
+func upsertUser(ctx context.Context, tx *sql.Tx, id, username, name, data string, now time.Time) error {
+	var dataArg interface{}
+	if data != "" {
+		dataArg = data
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO users (id, username, name, data, first_seen_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			username = excluded.username,
+			name = excluded.name,
+			data = COALESCE(excluded.data, users.data),
+			updated_at = excluded.updated_at`,
+		id, username, name, dataArg, now.Unix(), now.Unix())
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO user_names (user_id, username, observed_at) VALUES (?, ?, ?)`,
+		id, username, now.Unix())
+	return err
+}
+
+// Tweet returns a single tweet by ID, or nil if it is not archived.
+func (a *Archive) Tweet(ctx context.Context, id string) (*twitterscraper.Tweet, error) {
+	tweets, err := a.queryTweets(ctx, `SELECT data FROM tweets WHERE id = ?`, id)
+	if err != nil || len(tweets) == 0 {
+		return nil, err
+	}
+	return tweets[0], nil
+}
+
+// TweetsByUser returns tweets of a user, newest first.
+func (a *Archive) TweetsByUser(ctx context.Context, userID string) ([]*twitterscraper.Tweet, error) {
+	return a.queryTweets(ctx, `SELECT data FROM tweets WHERE user_id = ? ORDER BY created_at DESC`, userID)
+}
+
+// TweetsByUsername returns tweets of a user by any username it was seen
+// with, newest first.
+func (a *Archive) TweetsByUsername(ctx context.Context, username string) ([]*twitterscraper.Tweet, error) {
+	return a.queryTweets(ctx, `
+		SELECT data FROM tweets WHERE user_id IN (
+			SELECT user_id FROM user_names WHERE username = ? COLLATE NOCASE
+		) ORDER BY created_at DESC`, username)
+}
+
+// TweetsByHashtag returns tweets with the hashtag, without the leading '#'
+// and case insensitive, newest first.
+func (a *Archive) TweetsByHashtag(ctx context.Context, hashtag string) ([]*twitterscraper.Tweet, error) {
+	return a.queryTweets(ctx, `
+		SELECT t.data FROM tweets t JOIN hashtags h ON h.tweet_id = t.id
+		WHERE h.tag = ? ORDER BY t.created_at DESC`, strings.TrimPrefix(hashtag, "#"))
+}
+
+// TweetsByConversation returns the tweets of a conversation, oldest first.
+func (a *Archive) TweetsByConversation(ctx context.Context, conversationID string) ([]*twitterscraper.Tweet, error) {
+	return a.queryTweets(ctx, `SELECT data FROM tweets WHERE conversation_id = ? ORDER BY created_at, id`, conversationID)
+}
+
+// TweetsBetween returns tweets created in [from, to), oldest first.
+func (a *Archive) TweetsBetween(ctx context.Context, from, to time.Time) ([]*twitterscraper.Tweet, error) {
+	return a.queryTweets(ctx, `SELECT data FROM tweets WHERE created_at >= ? AND created_at < ? ORDER BY created_at, id`,
+		from.Unix(), to.Unix())
+}
+
+// Referencing returns the tweets that reference id through an edge of the
+// given kind, e.g. the replies to a tweet for EdgeReply or its quote tweets
+// for EdgeQuote.
+func (a *Archive) Referencing(ctx context.Context, id, kind string) ([]*twitterscraper.Tweet, error) {
+	return a.queryTweets(ctx, `
+		SELECT t.data FROM tweets t JOIN edges e ON e.tweet_id = t.id
+		WHERE e.target_id = ? AND e.kind = ? ORDER BY t.created_at, t.id`, id, kind)
+}
+
+// EngagementHistory returns the observed counters of a tweet, oldest first.
+func (a *Archive) EngagementHistory(ctx context.Context, tweetID string) ([]Engagement, error) {
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT observed_at, likes, retweets, replies, views FROM engagement_history
+		WHERE tweet_id = ? ORDER BY observed_at, rowid`, tweetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []Engagement
+	for rows.Next() {
+		var e Engagement
+		var observedAt int64
+		if err := rows.Scan(&observedAt, &e.Likes, &e.Retweets, &e.Replies, &e.Views); err != nil {
+			return nil, err
+		}
+		e.ObservedAt = time.UnixMilli(observedAt).UTC()
+		history = append(history, e)
+	}
+	return history, rows.Err()
+}
+
+// Profile returns an archived profile by user ID, or nil if only the
+// author name of its tweets or nothing at all is archived.
+func (a *Archive) Profile(ctx context.Context, userID string) (*twitterscraper.Profile, error) {
+	var data sql.NullString
+	err := a.db.QueryRowContext(ctx, `SELECT data FROM users WHERE id = ?`, userID).Scan(&data)
+	if err == sql.ErrNoRows || (err == nil && !data.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profile twitterscraper.Profile
+	if err := json.Unmarshal([]byte(data.String), &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (a *Archive) queryTweets(ctx context.Context, query string, args ...interface{}) ([]*twitterscraper.Tweet, error) {
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tweets []*twitterscraper.Tweet
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var tweet twitterscraper.Tweet
+		if err := json.Unmarshal([]byte(data), &tweet); err != nil {
+			return nil, err
+		}
+		tweets = append(tweets, &tweet)
+	}
+	return tweets, rows.Err()
+}
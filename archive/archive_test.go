@@ -0,0 +1,210 @@
+package archive_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/masa-finance/masa-twitter-scraper/archive"
+)
+
+func openArchive(t *testing.T) *archive.Archive {
+	a, err := archive.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+func newTweet(id string, ts int64) *twitterscraper.Tweet {
+	return &twitterscraper.Tweet{
+		ConversationID: "100",
+		ID:             id,
+		Text:           "tweet " + id,
+		TimeParsed:     time.Unix(ts, 0).UTC(),
+		Timestamp:      ts,
+		UserID:         "783214",
+		Username:       "Twitter",
+		Name:           "Twitter",
+	}
+}
+
+func TestSaveTweetUpsert(t *testing.T) {
+	ctx := context.Background()
+	a := openArchive(t)
+
+	root := newTweet("100", 1000)
+	reply := newTweet("101", 2000)
+	reply.Hashtags = []string{"Golang"}
+	reply.InReplyToStatus = root
+	reply.InReplyToStatusID = root.ID
+	reply.IsReply = true
+	reply.Likes = 1
+	reply.Photos = []twitterscraper.Photo{{ID: "5", URL: "https://pbs.twimg.com/media/a.jpg"}}
+
+	if err := a.SaveTweet(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	// Same counters: no new history entry.
+	if err := a.SaveTweet(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	reply.Likes = 5
+	reply.Views = 10
+	if err := a.SaveTweet(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := a.Tweet(ctx, "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Likes != 5 || got.Views != 10 {
+		t.Fatalf("Expected updated tweet, got %+v", got)
+	}
+
+	history, err := a.EngagementHistory(ctx, "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(history))
+	}
+	if history[0].Likes != 1 || history[1].Likes != 5 {
+		t.Errorf("Unexpected history %+v", history)
+	}
+
+	conversation, err := a.TweetsByConversation(ctx, "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conversation) != 2 || conversation[0].ID != "100" || conversation[1].ID != "101" {
+		t.Errorf("Expected referenced tweet to be archived in conversation order, got %d tweets", len(conversation))
+	}
+
+	replies, err := a.Referencing(ctx, "100", archive.EdgeReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replies) != 1 || replies[0].ID != "101" {
+		t.Errorf("Expected reply edge from 101, got %v", replies)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	ctx := context.Background()
+	a := openArchive(t)
+
+	first := newTweet("1", 1000)
+	first.Hashtags = []string{"go"}
+	second := newTweet("2", 2000)
+	second.Hashtags = []string{"Go", "sqlite"}
+	other := newTweet("3", 3000)
+	other.UserID = "42"
+	other.Username = "someone"
+
+	tweets := make(chan *twitterscraper.TweetResult, 3)
+	for _, tweet := range []*twitterscraper.Tweet{first, second, other} {
+		tweets <- &twitterscraper.TweetResult{Tweet: *tweet}
+	}
+	close(tweets)
+	if n, err := a.SaveTweets(ctx, tweets); err != nil || n != 3 {
+		t.Fatalf("SaveTweets() = %d, %v", n, err)
+	}
+
+	byTag, err := a.TweetsByHashtag(ctx, "#GO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byTag) != 2 || byTag[0].ID != "2" {
+		t.Errorf("Expected 2 tweets by hashtag newest first, got %d", len(byTag))
+	}
+
+	byUser, err := a.TweetsByUser(ctx, "783214")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byUser) != 2 {
+		t.Errorf("Expected 2 tweets by user, got %d", len(byUser))
+	}
+
+	between, err := a.TweetsBetween(ctx, time.Unix(1500, 0), time.Unix(3000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(between) != 1 || between[0].ID != "2" {
+		t.Errorf("Expected only tweet 2 in range, got %d", len(between))
+	}
+	if !between[0].TimeParsed.Equal(time.Unix(2000, 0)) {
+		t.Errorf("Expected TimeParsed restored, got %v", between[0].TimeParsed)
+	}
+}
+
+func TestSaveProfileRename(t *testing.T) {
+	ctx := context.Background()
+	a := openArchive(t)
+
+	if err := a.SaveTweet(ctx, newTweet("1", 1000)); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := a.Profile(ctx, "783214"); err != nil || p != nil {
+		t.Fatalf("Expected no profile for tweet author, got %v, %v", p, err)
+	}
+
+	profile := &twitterscraper.Profile{UserID: "783214", Username: "X", Name: "X", FollowersCount: 10}
+	if err := a.SaveProfile(ctx, profile); err != nil {
+		t.Fatal(err)
+	}
+	got, err := a.Profile(ctx, "783214")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.FollowersCount != 10 {
+		t.Fatalf("Expected saved profile, got %+v", got)
+	}
+
+	byOldName, err := a.TweetsByUsername(ctx, "twitter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byOldName) != 1 {
+		t.Errorf("Expected tweet by former username, got %d", len(byOldName))
+	}
+}
+
+func TestOpenChecksSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.db")
+	a, err := archive.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SaveTweet(context.Background(), newTweet("1", 1620000000)); err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+
+	// Reopening an archive of the same version keeps its data.
+	a, err = archive.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	var n int
+	if err := a.DB().QueryRow("SELECT COUNT(*) FROM tweets").Scan(&n); err != nil || n != 1 {
+		t.Errorf("Expected 1 tweet after reopening, got %d %v", n, err)
+	}
+	if _, err := a.DB().Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+
+	if a, err := archive.Open(path); err == nil {
+		a.Close()
+		t.Fatal("Expected an error for another schema version")
+	} else if !strings.Contains(err.Error(), "schema version 99") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package archive
+
+// schemaVersion is stored in PRAGMA user_version.
+const schemaVersion = 1
+
+// schema creates the archive tables. Every tweet row keeps the full tweet
+// encoded with the twitterscraper JSON schema in data; the other tables
+// index it for queries.
+const schema = `
+CREATE TABLE IF NOT EXISTS users (
+	id              TEXT PRIMARY KEY,
+	username        TEXT NOT NULL,
+	name            TEXT NOT NULL DEFAULT '',
+	data            TEXT,
+	first_seen_at   INTEGER NOT NULL,
+	updated_at      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS users_username ON users (username COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS user_names (
+	user_id     TEXT NOT NULL REFERENCES users (id),
+	username    TEXT NOT NULL,
+	observed_at INTEGER NOT NULL,
+	PRIMARY KEY (user_id, username)
+);
+
+CREATE TABLE IF NOT EXISTS tweets (
+	id              TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL DEFAULT '',
+	user_id         TEXT NOT NULL DEFAULT '',
+	created_at      INTEGER NOT NULL DEFAULT 0,
+	text            TEXT NOT NULL DEFAULT '',
+	likes           INTEGER NOT NULL DEFAULT 0,
+	retweets        INTEGER NOT NULL DEFAULT 0,
+	replies         INTEGER NOT NULL DEFAULT 0,
+	views           INTEGER NOT NULL DEFAULT 0,
+	data            TEXT NOT NULL,
+	first_seen_at   INTEGER NOT NULL,
+	updated_at      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS tweets_user ON tweets (user_id, created_at);
+CREATE INDEX IF NOT EXISTS tweets_conversation ON tweets (conversation_id, created_at);
+CREATE INDEX IF NOT EXISTS tweets_created_at ON tweets (created_at);
+
+CREATE TABLE IF NOT EXISTS engagement_history (
+	tweet_id    TEXT NOT NULL REFERENCES tweets (id),
+	observed_at INTEGER NOT NULL,
+	likes       INTEGER NOT NULL,
+	retweets    INTEGER NOT NULL,
+	replies     INTEGER NOT NULL,
+	views       INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS engagement_history_tweet ON engagement_history (tweet_id, observed_at);
+
+CREATE TABLE IF NOT EXISTS media (
+	id       TEXT NOT NULL,
+	tweet_id TEXT NOT NULL REFERENCES tweets (id),
+	type     TEXT NOT NULL,
+	url      TEXT NOT NULL,
+	preview  TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (tweet_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS mentions (
+	tweet_id TEXT NOT NULL REFERENCES tweets (id),
+	user_id  TEXT NOT NULL,
+	username TEXT NOT NULL,
+	PRIMARY KEY (tweet_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS mentions_user ON mentions (user_id);
+
+CREATE TABLE IF NOT EXISTS hashtags (
+	tweet_id TEXT NOT NULL REFERENCES tweets (id),
+	tag      TEXT NOT NULL COLLATE NOCASE,
+	PRIMARY KEY (tweet_id, tag)
+);
+CREATE INDEX IF NOT EXISTS hashtags_tag ON hashtags (tag);
+
+CREATE TABLE IF NOT EXISTS edges (
+	tweet_id  TEXT NOT NULL REFERENCES tweets (id),
+	kind      TEXT NOT NULL,
+	target_id TEXT NOT NULL,
+	PRIMARY KEY (tweet_id, kind, target_id)
+);
+CREATE INDEX IF NOT EXISTS edges_target ON edges (target_id, kind);
+`
@@ -146,6 +146,39 @@ func (s *Scraper) IsLoggedIn() bool {
 	return s.isLogged
 }
 
+// VerifyLogin reports whether the session is still logged in, like
+// IsLoggedIn, but without changing the scraper: it is safe to call while
+// other requests use it.
+func (s *Scraper) VerifyLogin() bool {
+	req, err := http.NewRequest("GET", verifyCredentialsURL, nil)
+	if err != nil {
+		return false
+	}
+	if s.userAgent != "" {
+		req.Header.Set("User-Agent", s.userAgent)
+	}
+	if s.oAuthToken != "" && s.oAuthSecret != "" {
+		req.Header.Set("Authorization", s.sign(req.Method, req.URL))
+	} else {
+		req.Header.Set("Authorization", "Bearer "+bearerToken2)
+	}
+	s.setCSRFToken(req)
+
+	resp, err := s.getHTTPClient().Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	var verify verifyCredentials
+	if err := json.NewDecoder(resp.Body).Decode(&verify); err != nil {
+		return false
+	}
+	return verify.Errors == nil
+}
+
 // randomDelay introduces a random delay between 1 and 3 seconds
 func randomDelay() {
 	delay := time.Duration(3000+rand.Intn(5000)) * time.Millisecond
@@ -0,0 +1,89 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/url"
+)
+
+type bookmarkTimeline struct {
+	Data struct {
+		BookmarkTimelineV2 struct {
+			Timeline struct {
+				Instructions []instruction `json:"instructions"`
+			} `json:"timeline"`
+		} `json:"bookmark_timeline_v2"`
+	} `json:"data"`
+}
+
+// GetBookmarks returns channel with tweets bookmarked by the logged in user.
+// Requires a session logged in with a user account.
+func (s *Scraper) GetBookmarks(ctx context.Context, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, "", maxTweetsNbr, func(_ string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+		return s.FetchBookmarks(maxTweetsNbr, cursor)
+	})
+}
+
+// FetchBookmarks gets tweets bookmarked by the logged in user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchBookmarks(maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	if err := s.requireUserSession(); err != nil {
+		return nil, "", err
+	}
+	if maxTweetsNbr > 100 {
+		maxTweetsNbr = 100
+	}
+
+	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/xLjCVTqYWz8CGSprLU349w/Bookmarks")
+	if err != nil {
+		return nil, "", err
+	}
+
+	variables := map[string]interface{}{
+		"count":                  maxTweetsNbr,
+		"includePromotedContent": false,
+	}
+	features := map[string]interface{}{
+		"graphql_timeline_v2_bookmark_timeline":                                   true,
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var timeline bookmarkTimeline
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tweets, nextCursor := parseTimelineTweets(timeline.Data.BookmarkTimelineV2.Timeline.Instructions)
+	return tweets, nextCursor, nil
+}
@@ -0,0 +1,126 @@
+package twitterscraper
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bindingValue is a value of a card, keyed by name in the card.
+type bindingValue struct {
+	Type         string `json:"type"`
+	StringValue  string `json:"string_value"`
+	BooleanValue bool   `json:"boolean_value"`
+	ImageValue   struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"image_value"`
+}
+
+// legacyCard is the card of the legacy API, with binding values by key.
+type legacyCard struct {
+	Name          string                  `json:"name"`
+	URL           string                  `json:"url"`
+	BindingValues map[string]bindingValue `json:"binding_values"`
+}
+
+// graphQLCard is the card of the GraphQL API, with binding values as a list
+// of key/value pairs.
+type graphQLCard struct {
+	Legacy struct {
+		Name          string `json:"name"`
+		URL           string `json:"url"`
+		BindingValues []struct {
+			Key   string       `json:"key"`
+			Value bindingValue `json:"value"`
+		} `json:"binding_values"`
+	} `json:"legacy"`
+}
+
+func (card *graphQLCard) toLegacy() legacyCard {
+	legacy := legacyCard{
+		Name:          card.Legacy.Name,
+		URL:           card.Legacy.URL,
+		BindingValues: make(map[string]bindingValue, len(card.Legacy.BindingValues)),
+	}
+	for _, value := range card.Legacy.BindingValues {
+		legacy.BindingValues[value.Key] = value.Value
+	}
+	return legacy
+}
+
+// thumbnailKeys are the image values used as the thumbnail of a card, by
+// preference.
+var thumbnailKeys = []string{
+	"thumbnail_image_original",
+	"summary_photo_image_original",
+	"photo_image_full_size_original",
+	"player_image_original",
+	"thumbnail_image_large",
+	"thumbnail_image",
+}
+
+// parse returns the card and, for poll cards, the poll. Both are nil when
+// the tweet has no card.
+func (card *legacyCard) parse() (*Card, *Poll) {
+	if card.Name == "" {
+		return nil, nil
+	}
+	// Some card names are prefixed with the ID of the card type, like
+	// "3691233323:audiospace".
+	name := card.Name
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	values := card.BindingValues
+	c := &Card{
+		Name:        name,
+		URL:         card.URL,
+		Title:       values["title"].StringValue,
+		Description: values["description"].StringValue,
+		Domain:      values["vanity_url"].StringValue,
+		PlayerURL:   values["player_url"].StringValue,
+	}
+	if c.URL == "" {
+		c.URL = values["card_url"].StringValue
+	}
+	if c.Domain == "" {
+		c.Domain = values["domain"].StringValue
+	}
+	for _, key := range thumbnailKeys {
+		if url := values[key].ImageValue.URL; url != "" {
+			c.Thumbnail = url
+			break
+		}
+	}
+	c.PlayerWidth, _ = strconv.Atoi(values["player_width"].StringValue)
+	c.PlayerHeight, _ = strconv.Atoi(values["player_height"].StringValue)
+	for key, value := range values {
+		if value.Type == "STRING" {
+			if c.Values == nil {
+				c.Values = make(map[string]string)
+			}
+			c.Values[key] = value.StringValue
+		}
+	}
+
+	if !strings.HasPrefix(name, "poll") {
+		return c, nil
+	}
+	poll := &Poll{Finished: values["counts_are_final"].BooleanValue}
+	for i := 1; ; i++ {
+		label, ok := values["choice"+strconv.Itoa(i)+"_label"]
+		if !ok {
+			break
+		}
+		count, _ := strconv.Atoi(values["choice"+strconv.Itoa(i)+"_count"].StringValue)
+		poll.Choices = append(poll.Choices, PollChoice{Label: label.StringValue, Count: count})
+	}
+	poll.DurationMinutes, _ = strconv.Atoi(values["duration_minutes"].StringValue)
+	if end, err := time.Parse(time.RFC3339, values["end_datetime_utc"].StringValue); err == nil {
+		poll.EndTime = end
+	}
+	return c, poll
+}
@@ -0,0 +1,83 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseLegacyCardPoll(t *testing.T) {
+	var timeline timelineV1
+	err := json.Unmarshal([]byte(`{"globalObjects": {
+		"tweets": {"1": {"id_str": "1", "user_id_str": "10", "card": {
+			"name": "poll3choice_text_only",
+			"url": "https://t.co/poll",
+			"binding_values": {
+				"choice1_label": {"type": "STRING", "string_value": "Yes"},
+				"choice1_count": {"type": "STRING", "string_value": "12"},
+				"choice2_label": {"type": "STRING", "string_value": "No"},
+				"choice2_count": {"type": "STRING", "string_value": "3"},
+				"choice3_label": {"type": "STRING", "string_value": "Maybe"},
+				"choice3_count": {"type": "STRING", "string_value": "0"},
+				"duration_minutes": {"type": "STRING", "string_value": "1440"},
+				"end_datetime_utc": {"type": "STRING", "string_value": "2024-01-02T15:04:05Z"},
+				"counts_are_final": {"type": "BOOLEAN", "boolean_value": true}
+			}
+		}}},
+		"users": {"10": {"screen_name": "alice"}}
+	}}`), &timeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweet := timeline.parseTweet("1")
+	if tweet.Card == nil || tweet.Card.Name != "poll3choice_text_only" {
+		t.Fatalf("Expected poll card, got %+v", tweet.Card)
+	}
+	poll := tweet.Poll
+	if poll == nil {
+		t.Fatal("Expected poll")
+	}
+	if len(poll.Choices) != 3 || poll.Choices[0] != (PollChoice{Label: "Yes", Count: 12}) || poll.Choices[2].Label != "Maybe" {
+		t.Errorf("Unexpected choices %+v", poll.Choices)
+	}
+	if !poll.Finished || poll.DurationMinutes != 1440 || !poll.EndTime.Equal(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("Unexpected poll %+v", poll)
+	}
+}
+
+func TestParseGraphQLCardSummary(t *testing.T) {
+	var res result
+	err := json.Unmarshal([]byte(`{
+		"__typename": "Tweet",
+		"legacy": {"id_str": "2", "full_text": "read this"},
+		"card": {"rest_id": "https://t.co/link", "legacy": {
+			"name": "summary_large_image",
+			"url": "https://t.co/link",
+			"binding_values": [
+				{"key": "title", "value": {"type": "STRING", "string_value": "Title"}},
+				{"key": "description", "value": {"type": "STRING", "string_value": "Description"}},
+				{"key": "vanity_url", "value": {"type": "STRING", "string_value": "example.com"}},
+				{"key": "thumbnail_image_large", "value": {"type": "IMAGE", "image_value": {"url": "https://pbs.twimg.com/large.jpg"}}},
+				{"key": "summary_photo_image_original", "value": {"type": "IMAGE", "image_value": {"url": "https://pbs.twimg.com/orig.jpg"}}}
+			]
+		}}
+	}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweet := res.parse()
+	if tweet.Poll != nil {
+		t.Errorf("Expected no poll, got %+v", tweet.Poll)
+	}
+	card := tweet.Card
+	if card == nil {
+		t.Fatal("Expected card")
+	}
+	if card.Name != "summary_large_image" || card.URL != "https://t.co/link" || card.Title != "Title" ||
+		card.Description != "Description" || card.Domain != "example.com" {
+		t.Errorf("Unexpected card %+v", card)
+	}
+	if card.Thumbnail != "https://pbs.twimg.com/orig.jpg" {
+		t.Errorf("Expected original thumbnail, got %s", card.Thumbnail)
+	}
+}
@@ -0,0 +1,110 @@
+// Command twitter-scraper-server serves the scraper as a REST API.
+//
+// Sessions are restored from cookie files saved with Scraper.GetCookies, one
+// file per account, or created by logging in with the TWITTER_USERNAME,
+// TWITTER_PASSWORD and TWITTER_EMAIL environment variables.
+//
+//	twitter-scraper-server -addr :8080 -api-keys key1,key2 -cookies a.json,b.json -quota 60
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/masa-finance/masa-twitter-scraper/server"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	apiKeys := flag.String("api-keys", os.Getenv("SCRAPER_API_KEYS"), "comma-separated list of API keys")
+	cookies := flag.String("cookies", "", "comma-separated list of cookie files, one per account")
+	quota := flag.Int("quota", 0, "requests allowed per API key and quota window, 0 disables quotas")
+	quotaWindow := flag.Duration("quota-window", time.Minute, "quota window")
+	proxy := flag.String("proxy", "", "http(s) or socks5 proxy used by every account")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		logrus.WithError(err).Warn("Error loading .env file")
+	}
+
+	newScraper := func() *twitterscraper.Scraper {
+		scraper := twitterscraper.New()
+		if *proxy != "" {
+			if err := scraper.SetProxy(*proxy); err != nil {
+				logrus.WithError(err).Fatal("Failed to set proxy")
+			}
+		}
+		return scraper
+	}
+
+	var scrapers []*twitterscraper.Scraper
+	if *cookies != "" {
+		for _, file := range splitList(*cookies) {
+			scraper, err := loadSession(newScraper(), file)
+			if err != nil {
+				logrus.WithError(err).WithField("file", file).Fatal("Failed to restore session")
+			}
+			scrapers = append(scrapers, scraper)
+		}
+	} else {
+		scraper := newScraper()
+		err := scraper.Login(os.Getenv("TWITTER_USERNAME"), os.Getenv("TWITTER_PASSWORD"), os.Getenv("TWITTER_EMAIL"))
+		if err != nil {
+			logrus.WithError(err).Fatal("Login failed")
+		}
+		scrapers = append(scrapers, scraper)
+	}
+
+	srv, err := server.New(server.NewPool(scrapers...), server.Config{
+		APIKeys:     splitList(*apiKeys),
+		Quota:       *quota,
+		QuotaWindow: *quotaWindow,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create server")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"addr":     *addr,
+		"accounts": len(scrapers),
+	}).Info("Starting server")
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		logrus.WithError(err).Fatal("Server stopped")
+	}
+}
+
+func loadSession(scraper *twitterscraper.Scraper, file string) (*twitterscraper.Scraper, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cookies []*http.Cookie
+	if err := json.NewDecoder(f).Decode(&cookies); err != nil {
+		return nil, err
+	}
+	scraper.SetCookies(cookies)
+	if !scraper.IsLoggedIn() {
+		return nil, fmt.Errorf("session from %s is not logged in", file)
+	}
+	return scraper, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
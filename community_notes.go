@@ -0,0 +1,143 @@
+package twitterscraper
+
+import (
+	"net/url"
+	"time"
+)
+
+// birdwatchText is a note text with the links it cites.
+type birdwatchText struct {
+	Text     string `json:"text"`
+	Entities []struct {
+		Ref struct {
+			Type    string `json:"type"`
+			URL     string `json:"url"`
+			URLType string `json:"urlType"`
+		} `json:"ref"`
+	} `json:"entities"`
+}
+
+func (text *birdwatchText) sources() []string {
+	var sources []string
+	for _, entity := range text.Entities {
+		if entity.Ref.URL != "" && entity.Ref.URLType != "DeepLink" {
+			sources = append(sources, entity.Ref.URL)
+		}
+	}
+	return sources
+}
+
+// birdwatchPivot is the note shown under a tweet.
+type birdwatchPivot struct {
+	DestinationURL string `json:"destinationUrl"`
+	Note           struct {
+		RestID string `json:"rest_id"`
+	} `json:"note"`
+	Subtitle birdwatchText `json:"subtitle"`
+}
+
+func (pivot *birdwatchPivot) parse() *CommunityNote {
+	if pivot.Note.RestID == "" && pivot.Subtitle.Text == "" {
+		return nil
+	}
+	note := &CommunityNote{
+		ID:   pivot.Note.RestID,
+		Text: pivot.Subtitle.Text,
+		URL:  pivot.DestinationURL,
+		// Only notes rated helpful are shown under tweets.
+		Status:  "CurrentlyRatedHelpful",
+		Sources: pivot.Subtitle.sources(),
+	}
+	if note.URL == "" && note.ID != "" {
+		note.URL = "https://twitter.com/i/birdwatch/n/" + note.ID
+	}
+	return note
+}
+
+type birdwatchNote struct {
+	RestID string `json:"rest_id"`
+	DataV1 struct {
+		Classification string        `json:"classification"`
+		Summary        birdwatchText `json:"summary"`
+	} `json:"data_v1"`
+	RatingStatus string `json:"rating_status"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+func (n *birdwatchNote) parse() CommunityNote {
+	note := CommunityNote{
+		ID:             n.RestID,
+		Text:           n.DataV1.Summary.Text,
+		URL:            "https://twitter.com/i/birdwatch/n/" + n.RestID,
+		Status:         n.RatingStatus,
+		Classification: n.DataV1.Classification,
+		Sources:        n.DataV1.Summary.sources(),
+	}
+	if n.CreatedAt > 0 {
+		created := time.UnixMilli(n.CreatedAt).UTC()
+		note.Created = &created
+	}
+	return note
+}
+
+type birdwatchNotes struct {
+	Data struct {
+		TweetResultByRestID struct {
+			Result struct {
+				MisleadingNotes struct {
+					Notes []birdwatchNote `json:"notes"`
+				} `json:"misleading_birdwatch_notes"`
+				NotMisleadingNotes struct {
+					Notes []birdwatchNote `json:"notes"`
+				} `json:"not_misleading_birdwatch_notes"`
+			} `json:"result"`
+		} `json:"tweet_result_by_rest_id"`
+	} `json:"data"`
+}
+
+// GetCommunityNotes returns all the Community Notes written on a tweet,
+// including the ones that are not shown under it, with their rating status.
+// Requires a session logged in with a user account that has access to
+// Community Notes.
+func (s *Scraper) GetCommunityNotes(tweetID string) ([]CommunityNote, error) {
+	if err := s.requireUserSession(); err != nil {
+		return nil, err
+	}
+
+	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/nSbQV9cD9Vd8bRy-fAAFsA/BirdwatchFetchNotes")
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]interface{}{
+		"tweet_id": tweetID,
+	}
+	features := map[string]interface{}{
+		"responsive_web_birdwatch_media_notes_enabled":                      true,
+		"responsive_web_graphql_exclude_directive_enabled":                  true,
+		"verified_phone_label_enabled":                                      false,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled": false,
+		"responsive_web_graphql_timeline_navigation_enabled":                true,
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var jsn birdwatchNotes
+	err = s.RequestAPI(req, &jsn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := jsn.Data.TweetResultByRestID.Result
+	notes := make([]CommunityNote, 0, len(result.MisleadingNotes.Notes)+len(result.NotMisleadingNotes.Notes))
+	for _, note := range result.MisleadingNotes.Notes {
+		notes = append(notes, note.parse())
+	}
+	for _, note := range result.NotMisleadingNotes.Notes {
+		notes = append(notes, note.parse())
+	}
+	return notes, nil
+}
@@ -0,0 +1,154 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseBirdwatchPivot(t *testing.T) {
+	var res result
+	err := json.Unmarshal([]byte(`{
+		"__typename": "Tweet",
+		"legacy": {"id_str": "1", "full_text": "claim"},
+		"birdwatch_pivot": {
+			"destinationUrl": "https://twitter.com/i/birdwatch/n/42",
+			"note": {"rest_id": "42"},
+			"subtitle": {"text": "Context https://t.co/src", "entities": [
+				{"fromIndex": 8, "toIndex": 24, "ref": {"type": "TimelineUrl", "url": "https://t.co/src", "urlType": "ExternalUrl"}}
+			]},
+			"title": "Readers added context"
+		}
+	}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := res.parse().CommunityNote
+	if note == nil {
+		t.Fatal("Expected community note")
+	}
+	if note.ID != "42" || note.Text != "Context https://t.co/src" || note.URL != "https://twitter.com/i/birdwatch/n/42" {
+		t.Errorf("Unexpected note %+v", note)
+	}
+	if len(note.Sources) != 1 || note.Sources[0] != "https://t.co/src" {
+		t.Errorf("Unexpected sources %v", note.Sources)
+	}
+}
+
+func TestGetCommunityNotes(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if id := requestVariables(t, req)["tweet_id"]; id != "1" {
+			t.Errorf("Expected tweet_id 1, got %v", id)
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"tweet_result_by_rest_id": map[string]interface{}{
+			"result": map[string]interface{}{
+				"misleading_birdwatch_notes": map[string]interface{}{"notes": []interface{}{
+					map[string]interface{}{
+						"rest_id":       "42",
+						"rating_status": "CurrentlyRatedHelpful",
+						"created_at":    1700000000000,
+						"data_v1": map[string]interface{}{
+							"classification": "MisinformedOrPotentiallyMisleading",
+							"summary":        map[string]interface{}{"text": "Shown note"},
+						},
+					},
+				}},
+				"not_misleading_birdwatch_notes": map[string]interface{}{"notes": []interface{}{
+					map[string]interface{}{
+						"rest_id":       "43",
+						"rating_status": "NeedsMoreRatings",
+						"data_v1": map[string]interface{}{
+							"classification": "NotMisleading",
+							"summary":        map[string]interface{}{"text": "Hidden note"},
+						},
+					},
+				}},
+			},
+		}}}
+	})
+	notes, err := scraper.GetCommunityNotes("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(notes))
+	}
+	if notes[0].ID != "42" || notes[0].Status != "CurrentlyRatedHelpful" || notes[0].Created == nil {
+		t.Errorf("Unexpected note %+v", notes[0])
+	}
+	if notes[1].Text != "Hidden note" || notes[1].Status != "NeedsMoreRatings" || notes[1].Classification != "NotMisleading" {
+		t.Errorf("Unexpected note %+v", notes[1])
+	}
+}
+
+func TestGetCommunityNotesRequiresLogin(t *testing.T) {
+	if _, err := New().GetCommunityNotes("1"); !errors.Is(err, ErrLoginRequired) {
+		t.Errorf("Expected ErrLoginRequired, got %v", err)
+	}
+}
+
+// searchResultFixture returns a tweet result with a community note, a card
+// and edit metadata.
+func searchResultFixture(id string) map[string]interface{} {
+	tweet := tweetResultJSON(id, "alice")
+	tweet["birdwatch_pivot"] = map[string]interface{}{
+		"destinationUrl": "https://twitter.com/i/birdwatch/n/" + id,
+		"note":           map[string]interface{}{"rest_id": "note-" + id},
+		"subtitle":       map[string]interface{}{"text": "Context"},
+	}
+	tweet["card"] = map[string]interface{}{"rest_id": "https://t.co/link", "legacy": map[string]interface{}{
+		"name": "summary",
+		"url":  "https://t.co/link",
+		"binding_values": []interface{}{
+			map[string]interface{}{"key": "title", "value": map[string]interface{}{"type": "STRING", "string_value": "Title"}},
+		},
+	}}
+	tweet["edit_control"] = map[string]interface{}{
+		"edit_tweet_ids":       []string{"0", id},
+		"editable_until_msecs": "1700000000000",
+		"edits_remaining":      "4",
+	}
+	return tweet
+}
+
+func TestSearchTweetsParseResults(t *testing.T) {
+	retweet := tweetResultJSON("2", "bob")
+	retweet["legacy"].(map[string]interface{})["retweeted_status_result"] = map[string]interface{}{"result": searchResultFixture("3")}
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		var entries []interface{}
+		for _, tweet := range []map[string]interface{}{searchResultFixture("1"), retweet} {
+			entries = append(entries, map[string]interface{}{
+				"entryId": "tweet-" + tweet["rest_id"].(string),
+				"content": map[string]interface{}{"itemContent": map[string]interface{}{
+					"tweetDisplayType": "Tweet",
+					"tweet_results":    map[string]interface{}{"result": tweet},
+				}},
+			})
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"search_by_raw_query": map[string]interface{}{
+			"search_timeline": map[string]interface{}{"timeline": map[string]interface{}{"instructions": []interface{}{
+				map[string]interface{}{"type": "TimelineAddEntries", "entries": entries},
+			}}},
+		}}}
+	})
+
+	tweets, _, err := scraper.FetchSearchTweets("golang", 20, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tweets) != 2 || tweets[1].RetweetedStatus == nil {
+		t.Fatalf("Expected a tweet and a retweet, got %+v", tweets)
+	}
+	for _, tweet := range []*Tweet{tweets[0], tweets[1].RetweetedStatus} {
+		if tweet.CommunityNote == nil || tweet.CommunityNote.ID != "note-"+tweet.ID {
+			t.Errorf("Tweet %s: expected community note, got %+v", tweet.ID, tweet.CommunityNote)
+		}
+		if tweet.Card == nil || tweet.Card.Title != "Title" {
+			t.Errorf("Tweet %s: expected card, got %+v", tweet.ID, tweet.Card)
+		}
+		if !tweet.IsEdited || tweet.EditableUntil == nil || len(tweet.EditHistoryIDs) != 2 {
+			t.Errorf("Tweet %s: expected edit metadata, got %+v", tweet.ID, tweet)
+		}
+	}
+}
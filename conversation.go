@@ -0,0 +1,234 @@
+package twitterscraper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ConversationNode is a tweet in the reply tree of a conversation.
+type ConversationNode struct {
+	Tweet    *Tweet
+	Parent   *ConversationNode
+	Children []*ConversationNode
+	// Depth is 0 for the root tweet, 1 for its direct replies and so on.
+	Depth int
+	// Rank is the position of the tweet in the order Twitter returned the
+	// conversation, which puts higher ranked replies first. Children are
+	// sorted by Rank.
+	Rank int
+}
+
+// Conversation is the reply tree of a tweet.
+type Conversation struct {
+	// Root is the tweet that started the conversation. When it is not
+	// available (deleted or protected), Root is the oldest loaded ancestor
+	// of the focal tweet.
+	Root *ConversationNode
+	// Focal is the tweet the conversation was requested for.
+	Focal *ConversationNode
+
+	nodes map[string]*ConversationNode
+}
+
+// Node returns the node of a tweet, or nil if the tweet is not part of the
+// conversation.
+func (c *Conversation) Node(id string) *ConversationNode {
+	return c.nodes[id]
+}
+
+// Len returns the number of tweets in the conversation.
+func (c *Conversation) Len() int {
+	return len(c.nodes)
+}
+
+// Walk calls fn for every node, depth first, children in rank order.
+func (c *Conversation) Walk(fn func(node *ConversationNode)) {
+	var walk func(node *ConversationNode)
+	walk = func(node *ConversationNode) {
+		fn(node)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if c.Root != nil {
+		walk(c.Root)
+	}
+}
+
+// GetConversation loads every reply of a conversation, following all the
+// "show more replies" cursors, and returns it as a tree.
+func (s *Scraper) GetConversation(ctx context.Context, tweetID string) (*Conversation, error) {
+	var tweets []*Tweet
+	for result := range s.StreamConversation(ctx, tweetID) {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		tweet := result.Tweet
+		tweets = append(tweets, &tweet)
+	}
+	conversation := newConversation(tweetID, tweets)
+	if conversation.Focal == nil {
+		return nil, fmt.Errorf("tweet with ID %s not found", tweetID)
+	}
+	return conversation, nil
+}
+
+// StreamConversation returns channel with every tweet of the conversation of
+// a tweet, in ranking order, as pages are loaded. Use it instead of
+// GetConversation for very large conversations.
+func (s *Scraper) StreamConversation(ctx context.Context, tweetID string) <-chan *TweetResult {
+	channel := make(chan *TweetResult)
+	go func() {
+		defer close(channel)
+		seenTweets := make(map[string]bool)
+		seenCursors := make(map[string]bool)
+		cursors := []string{""}
+		for len(cursors) > 0 {
+			select {
+			case <-ctx.Done():
+				channel <- &TweetResult{Error: ctx.Err()}
+				return
+			default:
+			}
+
+			cursor := cursors[0]
+			cursors = cursors[1:]
+			tweets, next, err := s.fetchConversationPage(tweetID, cursor)
+			if err != nil {
+				channel <- &TweetResult{Error: err}
+				return
+			}
+			for _, c := range next {
+				if !seenCursors[c] {
+					seenCursors[c] = true
+					cursors = append(cursors, c)
+				}
+			}
+
+			for _, tweet := range tweets {
+				if seenTweets[tweet.ID] {
+					continue
+				}
+				seenTweets[tweet.ID] = true
+				select {
+				case <-ctx.Done():
+					channel <- &TweetResult{Error: ctx.Err()}
+					return
+				case channel <- &TweetResult{Tweet: *tweet}:
+				}
+			}
+		}
+	}()
+	return channel
+}
+
+// fetchConversationPage loads a page of a conversation and returns its tweets
+// and the cursors of the pages it links to.
+func (s *Scraper) fetchConversationPage(tweetID string, cursor string) ([]*Tweet, []string, error) {
+	if s.isOpenAccount {
+		req, err := s.newRequest("GET", "https://api.twitter.com/2/timeline/conversation/"+tweetID+".json")
+		if err != nil {
+			return nil, nil, err
+		}
+		if cursor != "" {
+			q := req.URL.Query()
+			q.Add("cursor", cursor)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		var timeline timelineV1
+		err = s.RequestAPI(req, &timeline)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tweets, next := timeline.parseTweets()
+		if next == "" {
+			return tweets, nil, nil
+		}
+		return tweets, []string{next}, nil
+	}
+
+	conversation, err := s.fetchTweetDetail(tweetID, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conversation.parse(), conversation.cursors(), nil
+}
+
+// newConversation builds the reply tree of tweets, given in ranking order.
+// Tweets replying to a tweet that is not loaded are attached to the root.
+func newConversation(focalID string, tweets []*Tweet) *Conversation {
+	c := &Conversation{nodes: make(map[string]*ConversationNode, len(tweets))}
+	var order []*ConversationNode
+	for i, tweet := range tweets {
+		if _, ok := c.nodes[tweet.ID]; ok {
+			continue
+		}
+		node := &ConversationNode{Tweet: tweet, Rank: i}
+		c.nodes[tweet.ID] = node
+		order = append(order, node)
+	}
+	if len(order) == 0 {
+		return c
+	}
+	c.Focal = c.nodes[focalID]
+
+	// The root is the tweet starting the conversation, or else the oldest
+	// ancestor of the focal tweet that was loaded.
+	conversationID := order[0].Tweet.ConversationID
+	if c.Focal != nil {
+		conversationID = c.Focal.Tweet.ConversationID
+	}
+	c.Root = c.nodes[conversationID]
+	if c.Root == nil {
+		c.Root = c.Focal
+		if c.Root == nil {
+			c.Root = order[0]
+		}
+		for seen := map[string]bool{}; !seen[c.Root.Tweet.ID]; {
+			seen[c.Root.Tweet.ID] = true
+			parent := c.nodes[c.Root.Tweet.InReplyToStatusID]
+			if parent == nil {
+				break
+			}
+			c.Root = parent
+		}
+	}
+
+	for _, node := range order {
+		if node == c.Root {
+			continue
+		}
+		parent := c.nodes[node.Tweet.InReplyToStatusID]
+		if parent == nil || parent == node || isDescendant(parent, node) {
+			parent = c.Root
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	c.Walk(func(node *ConversationNode) {
+		if node.Parent != nil {
+			node.Depth = node.Parent.Depth + 1
+			if node.Parent.Tweet.ID == node.Tweet.InReplyToStatusID {
+				node.Tweet.InReplyToStatus = node.Parent.Tweet
+			}
+		}
+		sort.SliceStable(node.Children, func(i, j int) bool {
+			return node.Children[i].Rank < node.Children[j].Rank
+		})
+	})
+	return c
+}
+
+// isDescendant reports whether node is below ancestor in the tree built so far.
+func isDescendant(node, ancestor *ConversationNode) bool {
+	for p := node.Parent; p != nil; p = p.Parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package twitterscraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func loadConversationFixture(t *testing.T) *threadedConversation {
+	data, err := os.ReadFile("testdata/tweet_detail.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conversation threadedConversation
+	if err := json.Unmarshal(data, &conversation); err != nil {
+		t.Fatal(err)
+	}
+	return &conversation
+}
+
+func TestThreadedConversationCursors(t *testing.T) {
+	conversation := loadConversationFixture(t)
+	expected := []string{"showmore-1", "bottom-1", "showmorethreads-1"}
+	if diff := cmp.Diff(expected, conversation.cursors()); diff != "" {
+		t.Error("Resulting cursors does not match the sample", diff)
+	}
+}
+
+func TestNewConversation(t *testing.T) {
+	tweets := loadConversationFixture(t).parse()
+	orphan := &Tweet{ID: "200", ConversationID: "100", InReplyToStatusID: "199"}
+	c := newConversation("101", append(tweets, orphan))
+
+	if c.Len() != 5 {
+		t.Fatalf("Expected 5 tweets, got %d", c.Len())
+	}
+	if c.Root.Tweet.ID != "100" || c.Focal.Tweet.ID != "101" {
+		t.Errorf("Expected root 100 and focal 101, got %s and %s", c.Root.Tweet.ID, c.Focal.Tweet.ID)
+	}
+
+	var walked []string
+	depths := make(map[string]int)
+	c.Walk(func(node *ConversationNode) {
+		walked = append(walked, node.Tweet.ID)
+		depths[node.Tweet.ID] = node.Depth
+	})
+	if diff := cmp.Diff([]string{"100", "101", "102", "103", "200"}, walked); diff != "" {
+		t.Error("Walk order does not match", diff)
+	}
+	if diff := cmp.Diff(map[string]int{"100": 0, "101": 1, "102": 2, "103": 3, "200": 1}, depths); diff != "" {
+		t.Error("Depths do not match", diff)
+	}
+	if nested := c.Node("103"); nested.Parent != c.Node("102") || nested.Tweet.InReplyToStatus != c.Node("102").Tweet {
+		t.Error("Expected 103 to be linked to its parent 102")
+	}
+	if c.Node("200").Tweet.InReplyToStatus != nil {
+		t.Error("Expected orphan reply not linked to the root tweet")
+	}
+}
+
+// conversationReply encodes a reply as a TweetDetail entry.
+func conversationReply(id, replyTo string) map[string]interface{} {
+	tweet := tweetResultJSON(id, "alice")
+	legacy := tweet["legacy"].(map[string]interface{})
+	legacy["conversation_id_str"] = "100"
+	legacy["in_reply_to_status_id_str"] = replyTo
+	return map[string]interface{}{
+		"entryId": "tweet-" + id,
+		"content": map[string]interface{}{"itemContent": map[string]interface{}{
+			"tweet_results": map[string]interface{}{"result": tweet},
+		}},
+	}
+}
+
+func conversationCursor(cursorType, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"entryId": "cursor-" + value,
+		"content": map[string]interface{}{"itemContent": map[string]interface{}{
+			"itemType": "TimelineTimelineCursor", "cursorType": cursorType, "value": value,
+		}},
+	}
+}
+
+func TestStreamConversationPages(t *testing.T) {
+	pages := map[string][]interface{}{
+		"": {
+			conversationReply("100", ""),
+			conversationReply("101", "100"),
+			map[string]interface{}{"entryId": "conversationthread-102", "content": map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"item": conversationReply("102", "101")["content"]},
+				map[string]interface{}{"item": map[string]interface{}{"itemContent": map[string]interface{}{
+					"itemType": "TimelineTimelineCursor", "cursorType": "ShowMore", "value": "showmore-1",
+				}}},
+			}}},
+			conversationCursor("Bottom", "bottom-1"),
+		},
+		"showmore-1": {conversationReply("101", "100"), conversationReply("103", "102"), conversationCursor("Bottom", "bottom-1")},
+		"bottom-1":   {conversationReply("104", "100"), conversationCursor("ShowMoreThreads", "showmorethreads-1")},
+		"showmorethreads-1": {
+			conversationReply("105", "104"),
+			conversationCursor("ShowMore", "showmore-1"),
+		},
+	}
+	var fetched []string
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		cursor, _ := requestVariables(t, req)["cursor"].(string)
+		fetched = append(fetched, cursor)
+		return map[string]interface{}{"data": map[string]interface{}{
+			"threaded_conversation_with_injections_v2": map[string]interface{}{
+				"instructions": []interface{}{map[string]interface{}{"type": "TimelineAddEntries", "entries": pages[cursor]}},
+			},
+		}}
+	})
+
+	var tweets []*Tweet
+	for result := range scraper.StreamConversation(context.Background(), "101") {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		tweet := result.Tweet
+		tweets = append(tweets, &tweet)
+	}
+	if diff := cmp.Diff([]string{"", "showmore-1", "bottom-1", "showmorethreads-1"}, fetched); diff != "" {
+		t.Error("Each cursor should be fetched once", diff)
+	}
+
+	c := newConversation("101", tweets)
+	if c.Len() != 6 {
+		t.Fatalf("Expected 6 tweets, got %d", c.Len())
+	}
+	parents := make(map[string]string)
+	c.Walk(func(node *ConversationNode) {
+		if node.Parent != nil {
+			parents[node.Tweet.ID] = node.Parent.Tweet.ID
+		}
+	})
+	want := map[string]string{"101": "100", "102": "101", "103": "102", "104": "100", "105": "104"}
+	if diff := cmp.Diff(want, parents); diff != "" {
+		t.Error("Reply tree does not match", diff)
+	}
+}
@@ -0,0 +1,71 @@
+package twitterscraper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type editControlInfo struct {
+	EditTweetIDs       []string `json:"edit_tweet_ids"`
+	EditableUntilMsecs string   `json:"editable_until_msecs"`
+	EditsRemaining     string   `json:"edits_remaining"`
+	IsEditEligible     bool     `json:"is_edit_eligible"`
+}
+
+// editControl is the edit metadata of a tweet. The latest version carries
+// it directly; previous versions carry the metadata of the initial tweet in
+// EditControlInitial.
+type editControl struct {
+	editControlInfo
+	InitialTweetID     string           `json:"initial_tweet_id"`
+	EditControlInitial *editControlInfo `json:"edit_control_initial"`
+}
+
+// apply sets the edit fields of a tweet.
+func (control *editControl) apply(tw *Tweet) {
+	info := &control.editControlInfo
+	if control.EditControlInitial != nil {
+		info = control.EditControlInitial
+	}
+	if len(info.EditTweetIDs) > 0 {
+		tw.EditHistoryIDs = append([]string(nil), info.EditTweetIDs...)
+		tw.IsEdited = len(info.EditTweetIDs) > 1
+	}
+	if msecs, err := strconv.ParseInt(info.EditableUntilMsecs, 10, 64); err == nil && msecs > 0 {
+		until := time.UnixMilli(msecs).UTC()
+		tw.EditableUntil = &until
+	}
+	tw.EditsRemaining, _ = strconv.Atoi(info.EditsRemaining)
+}
+
+// GetTweetEditHistory returns every version of an edited tweet, oldest
+// first, the last one being the current version. The tweet can be any of
+// the versions. A tweet that was never edited is returned alone.
+func (s *Scraper) GetTweetEditHistory(ctx context.Context, tweetID string) ([]*Tweet, error) {
+	results, err := s.LookupTweets(ctx, []string{tweetID})
+	if err != nil {
+		return nil, err
+	}
+	if results[0].Error != nil {
+		return nil, results[0].Error
+	}
+	tweet := results[0].Tweet
+	if len(tweet.EditHistoryIDs) <= 1 {
+		return []*Tweet{&tweet}, nil
+	}
+
+	results, err = s.LookupTweets(ctx, tweet.EditHistoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*Tweet, 0, len(results))
+	for i := range results {
+		if results[i].Error != nil {
+			return nil, fmt.Errorf("version %s of tweet %s: %w", tweet.EditHistoryIDs[i], tweetID, results[i].Error)
+		}
+		versions = append(versions, &results[i].Tweet)
+	}
+	return versions, nil
+}
@@ -0,0 +1,72 @@
+package twitterscraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func editedTweetJSON(id string) map[string]interface{} {
+	tweet := tweetResultJSON(id, "alice")
+	if id == "3" {
+		tweet["edit_control"] = map[string]interface{}{
+			"edit_tweet_ids":       []string{"1", "3"},
+			"editable_until_msecs": "1700000000000",
+			"edits_remaining":      "4",
+			"is_edit_eligible":     true,
+		}
+	} else {
+		tweet["edit_control"] = map[string]interface{}{
+			"initial_tweet_id": "1",
+			"edit_control_initial": map[string]interface{}{
+				"edit_tweet_ids":       []string{"1", "3"},
+				"editable_until_msecs": "1700000000000",
+				"edits_remaining":      "4",
+			},
+		}
+	}
+	return tweet
+}
+
+func TestGetTweetEditHistory(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		variables := requestVariables(t, req)
+		if id, ok := variables["tweetId"]; ok {
+			return map[string]interface{}{"data": map[string]interface{}{"tweetResult": map[string]interface{}{
+				"result": editedTweetJSON(id.(string)),
+			}}}
+		}
+		var results []interface{}
+		for _, id := range variables["tweetIds"].([]interface{}) {
+			results = append(results, map[string]interface{}{"result": editedTweetJSON(id.(string))})
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"tweetResult": results}}
+	})
+	versions, err := scraper.GetTweetEditHistory(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].ID != "1" || versions[1].ID != "3" {
+		t.Fatalf("Unexpected versions %+v", versions)
+	}
+	latest := versions[1]
+	if !latest.IsEdited || latest.EditsRemaining != 4 || latest.EditableUntil == nil || latest.EditableUntil.UnixMilli() != 1700000000000 {
+		t.Errorf("Unexpected edit metadata %+v", latest)
+	}
+	if !versions[0].IsEdited || len(versions[0].EditHistoryIDs) != 2 {
+		t.Errorf("Expected initial version to carry the edit history, got %v", versions[0].EditHistoryIDs)
+	}
+}
+
+func TestGetTweetEditHistoryCanceled(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		t.Error("Unexpected request with a canceled context")
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := scraper.GetTweetEditHistory(ctx, "1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
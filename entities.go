@@ -0,0 +1,295 @@
+package twitterscraper
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// EntityType is the kind of an Entity.
+type EntityType string
+
+// Entity types.
+const (
+	EntityHashtag EntityType = "hashtag"
+	EntityCashtag EntityType = "cashtag"
+	EntityMention EntityType = "mention"
+	EntityURL     EntityType = "url"
+	EntityMedia   EntityType = "media"
+)
+
+// parseEntities returns the entities of a tweet, sorted by offset. The
+// indices given by Twitter are used as hints only: they don't account for
+// the HTML escaping of the text ("&amp;"), and they refer to the truncated
+// text for long tweets. Entities are located in text instead.
+func parseEntities(text string, tweet *legacyTweet) []Entity {
+	runes := []rune(text)
+	var entities []Entity
+	add := func(entity Entity, prefixes string, indices []int) {
+		hint := 0
+		if len(indices) > 0 {
+			hint = indices[0]
+		}
+		start, end, ok := locate(runes, entity.Text, prefixes, hint)
+		if !ok {
+			return
+		}
+		entity.Start, entity.End = start, end
+		entities = append(entities, entity)
+	}
+
+	for _, hashtag := range tweet.Entities.Hashtags {
+		add(Entity{Type: EntityHashtag, Text: hashtag.Text}, "#＃", hashtag.Indices)
+	}
+	for _, symbol := range tweet.Entities.Symbols {
+		add(Entity{Type: EntityCashtag, Text: symbol.Text}, "$", symbol.Indices)
+	}
+	for _, mention := range tweet.Entities.UserMentions {
+		add(Entity{Type: EntityMention, Text: mention.ScreenName, UserID: mention.IDStr}, "@＠", mention.Indices)
+	}
+	for _, u := range tweet.Entities.URLs {
+		add(Entity{Type: EntityURL, Text: u.URL, ExpandedURL: u.ExpandedURL, DisplayURL: u.DisplayURL}, "", u.Indices)
+	}
+	seenMedia := make(map[string]bool)
+	for _, media := range tweet.Entities.Media {
+		if seenMedia[media.URL] {
+			continue
+		}
+		seenMedia[media.URL] = true
+		add(Entity{
+			Type:        EntityMedia,
+			Text:        media.URL,
+			ExpandedURL: media.ExpandedURL,
+			DisplayURL:  media.DisplayURL,
+			MediaURL:    media.MediaURLHttps,
+		}, "", media.Indices)
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].Start < entities[j].Start
+	})
+	// Drop entities overlapping a previous one, like a mention matched
+	// inside a URL.
+	kept := entities[:0]
+	end := 0
+	for _, entity := range entities {
+		if entity.Start >= end {
+			kept = append(kept, entity)
+			end = entity.End
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// locate finds body in text, preceded by one of prefixes when prefixes is
+// not empty, and returns the offsets of the occurrence closest to hint.
+func locate(text []rune, body string, prefixes string, hint int) (int, int, bool) {
+	want := []rune(body)
+	if len(want) == 0 {
+		return 0, 0, false
+	}
+	best := -1
+	for i := 0; i+len(want) <= len(text); i++ {
+		if !equalFoldRunes(text[i:i+len(want)], want) {
+			continue
+		}
+		start := i
+		if prefixes != "" {
+			if i == 0 || !strings.ContainsRune(prefixes, text[i-1]) {
+				continue
+			}
+			start = i - 1
+		}
+		if best < 0 || abs(start-hint) < abs(best-hint) {
+			best = start
+		}
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+	end := best + len(want)
+	if prefixes != "" {
+		end++
+	}
+	return best, end, true
+}
+
+func equalFoldRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] && unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// walkText renders the visible text of a tweet: the text after the reply
+// prefix hidden by DisplayTextRange, with each entity replaced by
+// renderEntity and the text between entities by renderText.
+func (tweet *Tweet) walkText(renderText func(s string) string, renderEntity func(entity Entity, raw string) string) string {
+	runes := []rune(tweet.Text)
+	pos := 0
+	if len(tweet.DisplayTextRange) == 2 && tweet.DisplayTextRange[0] > 0 && tweet.DisplayTextRange[0] <= len(runes) {
+		pos = tweet.DisplayTextRange[0]
+	}
+	var b strings.Builder
+	for _, entity := range tweet.Entities {
+		if entity.Start < pos || entity.End > len(runes) {
+			continue
+		}
+		b.WriteString(renderText(string(runes[pos:entity.Start])))
+		b.WriteString(renderEntity(entity, string(runes[entity.Start:entity.End])))
+		pos = entity.End
+	}
+	b.WriteString(renderText(string(runes[pos:])))
+	return b.String()
+}
+
+// mediaImages returns the images of the photos, videos and GIFs of a tweet.
+func (tweet *Tweet) mediaImages() []string {
+	var images []string
+	for _, photo := range tweet.Photos {
+		images = append(images, photo.URL)
+	}
+	for _, video := range tweet.Videos {
+		images = append(images, video.Preview)
+	}
+	for _, gif := range tweet.GIFs {
+		images = append(images, gif.Preview)
+	}
+	return images
+}
+
+// escapeText escapes the characters that are special in HTML text. The
+// tweet text is already escaped by Twitter, so it is unescaped first.
+func escapeText(s string) string {
+	s = html.UnescapeString(s)
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// isWebURL reports whether rawURL is an http or https URL, the only ones
+// rendered as links: a URL from a URLResolver could be a javascript: one.
+func isWebURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (strings.EqualFold(u.Scheme, "http") || strings.EqualFold(u.Scheme, "https"))
+}
+
+// RenderHTML renders the tweet as HTML, with links for the entities and
+// the media images. The text is escaped, and the mentions of the reply
+// prefix are left out. It is the value of the HTML field.
+func (tweet *Tweet) RenderHTML() string {
+	var foundMedia []string
+	text := tweet.walkText(func(s string) string {
+		return strings.ReplaceAll(escapeText(s), "\n", "<br>")
+	}, func(entity Entity, raw string) string {
+		attr := html.EscapeString
+		switch entity.Type {
+		case EntityHashtag:
+			return fmt.Sprintf(`<a href="https://twitter.com/hashtag/%s">%s</a>`, attr(url.PathEscape(entity.Text)), escapeText(raw))
+		case EntityCashtag:
+			return fmt.Sprintf(`<a href="https://twitter.com/search?q=%s">%s</a>`, attr(url.QueryEscape("$"+entity.Text)), escapeText(raw))
+		case EntityMention:
+			return fmt.Sprintf(`<a href="https://twitter.com/%s">%s</a>`, attr(url.PathEscape(entity.Text)), escapeText(raw))
+		case EntityURL:
+			if !isWebURL(entity.ExpandedURL) {
+				return escapeText(raw)
+			}
+			return fmt.Sprintf(`<a href="%s">%s</a>`, attr(entity.ExpandedURL), escapeText(raw))
+		case EntityMedia:
+			if !isWebURL(entity.Text) || !isWebURL(entity.MediaURL) {
+				return escapeText(raw)
+			}
+			foundMedia = append(foundMedia, entity.MediaURL)
+			return fmt.Sprintf(`<br><a href="%s"><img src="%s"/></a>`, attr(entity.Text), attr(entity.MediaURL))
+		}
+		return escapeText(raw)
+	})
+
+	var b strings.Builder
+	b.WriteString(text)
+	for _, image := range tweet.mediaImages() {
+		if !stringInSlice(image, foundMedia) && isWebURL(image) {
+			fmt.Fprintf(&b, `<br><img src="%s"/>`, html.EscapeString(image))
+		}
+	}
+	return b.String()
+}
+
+// escapeMarkdown escapes the characters that are special in Markdown text.
+var escapeMarkdown = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+// escapeMarkdownURL percent-encodes the characters that end a Markdown link
+// destination.
+var escapeMarkdownURL = strings.NewReplacer(
+	"(", "%28", ")", "%29", " ", "%20", "<", "%3C", ">", "%3E", "\n", "%0A",
+)
+
+// RenderMarkdown renders the tweet as Markdown. Entities are links, t.co
+// links show their display URL, and the media images are appended.
+func (tweet *Tweet) RenderMarkdown() string {
+	text := tweet.walkText(func(s string) string {
+		return escapeMarkdown.Replace(html.UnescapeString(s))
+	}, func(entity Entity, raw string) string {
+		label := escapeMarkdown.Replace(html.UnescapeString(raw))
+		switch entity.Type {
+		case EntityHashtag:
+			return fmt.Sprintf("[%s](https://twitter.com/hashtag/%s)", label, url.PathEscape(entity.Text))
+		case EntityCashtag:
+			return fmt.Sprintf("[%s](https://twitter.com/search?q=%s)", label, url.QueryEscape("$"+entity.Text))
+		case EntityMention:
+			return fmt.Sprintf("[%s](https://twitter.com/%s)", label, url.PathEscape(entity.Text))
+		case EntityURL:
+			if !isWebURL(entity.ExpandedURL) {
+				return label
+			}
+			display := entity.DisplayURL
+			if display == "" {
+				display = entity.ExpandedURL
+			}
+			return fmt.Sprintf("[%s](%s)", escapeMarkdown.Replace(display), escapeMarkdownURL.Replace(entity.ExpandedURL))
+		case EntityMedia:
+			return ""
+		}
+		return label
+	})
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(text))
+	for _, image := range tweet.mediaImages() {
+		fmt.Fprintf(&b, "\n\n![](%s)", escapeMarkdownURL.Replace(image))
+	}
+	return b.String()
+}
+
+// RenderText renders the tweet as plain text: t.co links are replaced with
+// the URLs they expand to, media links are removed and HTML escapes are
+// decoded.
+func (tweet *Tweet) RenderText() string {
+	text := tweet.walkText(html.UnescapeString, func(entity Entity, raw string) string {
+		switch entity.Type {
+		case EntityURL:
+			if entity.ExpandedURL != "" {
+				return entity.ExpandedURL
+			}
+		case EntityMedia:
+			return ""
+		}
+		return html.UnescapeString(raw)
+	})
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,148 @@
+package twitterscraper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const entitiesTweetText = "@bob 🎉 Q&amp;A on #golang &amp; $TSLA: https://t.co/abc123 mail a@b.com &lt;b&gt;\nhttps://t.co/media1"
+
+func parseEntitiesTweet(t *testing.T) *Tweet {
+	t.Helper()
+	legacy := map[string]interface{}{
+		"id_str":             "1",
+		"full_text":          entitiesTweetText,
+		"display_text_range": []int{5, 64},
+		"entities": map[string]interface{}{
+			"hashtags":      []interface{}{map[string]interface{}{"text": "golang", "indices": []int{15, 22}}},
+			"symbols":       []interface{}{map[string]interface{}{"text": "TSLA", "indices": []int{25, 30}}},
+			"user_mentions": []interface{}{map[string]interface{}{"screen_name": "bob", "id_str": "2", "indices": []int{0, 4}}},
+			"urls": []interface{}{map[string]interface{}{
+				"url": "https://t.co/abc123", "expanded_url": "https://example.com/a?b=1&c=2", "display_url": "example.com/a?b=1…", "indices": []int{32, 51},
+			}},
+			"media": []interface{}{map[string]interface{}{
+				"url": "https://t.co/media1", "media_url_https": "https://pbs.twimg.com/media/x.jpg", "type": "photo", "indices": []int{74, 93},
+			}},
+		},
+		"extended_entities": map[string]interface{}{"media": []interface{}{
+			map[string]interface{}{"id_str": "3", "url": "https://t.co/media1", "media_url_https": "https://pbs.twimg.com/media/x.jpg", "type": "photo"},
+			map[string]interface{}{"id_str": "4", "url": "https://t.co/media1", "media_url_https": "https://pbs.twimg.com/media/y.jpg", "type": "photo"},
+		}},
+	}
+	data, _ := json.Marshal(map[string]interface{}{"__typename": "Tweet", "legacy": legacy})
+	var res result
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	return res.parse()
+}
+
+func runeIndex(s, substr string) int {
+	return utf8.RuneCountInString(s[:strings.Index(s, substr)])
+}
+
+func TestParseEntities(t *testing.T) {
+	tweet := parseEntitiesTweet(t)
+	want := []struct {
+		typ  EntityType
+		text string
+	}{
+		{EntityMention, "@bob"},
+		{EntityHashtag, "#golang"},
+		{EntityCashtag, "$TSLA"},
+		{EntityURL, "https://t.co/abc123"},
+		{EntityMedia, "https://t.co/media1"},
+	}
+	if len(tweet.Entities) != len(want) {
+		t.Fatalf("Expected %d entities, got %+v", len(want), tweet.Entities)
+	}
+	runes := []rune(tweet.Text)
+	for i, w := range want {
+		entity := tweet.Entities[i]
+		if entity.Type != w.typ || entity.Start != runeIndex(tweet.Text, w.text) || string(runes[entity.Start:entity.End]) != w.text {
+			t.Errorf("Entity %d: expected %s %q, got %+v", i, w.typ, w.text, entity)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	tweet := parseEntitiesTweet(t)
+	want := `🎉 Q&amp;A on <a href="https://twitter.com/hashtag/golang">#golang</a> &amp; ` +
+		`<a href="https://twitter.com/search?q=%24TSLA">$TSLA</a>: ` +
+		`<a href="https://example.com/a?b=1&amp;c=2">https://t.co/abc123</a> mail a@b.com &lt;b&gt;<br>` +
+		`<br><a href="https://t.co/media1"><img src="https://pbs.twimg.com/media/x.jpg"/></a>` +
+		`<br><img src="https://pbs.twimg.com/media/y.jpg"/>`
+	if tweet.HTML != want {
+		t.Errorf("Unexpected HTML\n got: %s\nwant: %s", tweet.HTML, want)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tweet := parseEntitiesTweet(t)
+	want := "🎉 Q&A on [#golang](https://twitter.com/hashtag/golang) & [$TSLA](https://twitter.com/search?q=%24TSLA): " +
+		"[example.com/a?b=1…](https://example.com/a?b=1&c=2) mail a@b.com \\<b\\>" +
+		"\n\n![](https://pbs.twimg.com/media/x.jpg)\n\n![](https://pbs.twimg.com/media/y.jpg)"
+	if got := tweet.RenderMarkdown(); got != want {
+		t.Errorf("Unexpected Markdown\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	tweet := parseEntitiesTweet(t)
+	want := "🎉 Q&A on #golang & $TSLA: https://example.com/a?b=1&c=2 mail a@b.com <b>"
+	if got := tweet.RenderText(); got != want {
+		t.Errorf("Unexpected text\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestRenderEscapesURLs(t *testing.T) {
+	tweet := &Tweet{
+		Text: "#café https://t.co/wiki",
+		Entities: []Entity{
+			{Type: EntityHashtag, Start: 0, End: 5, Text: "café"},
+			{Type: EntityURL, Start: 6, End: 23, Text: "https://t.co/wiki", ExpandedURL: "https://en.wikipedia.org/wiki/Go_(language) x", DisplayURL: "en.wikipedia.org/wiki/Go_(lang…"},
+		},
+		Photos: []Photo{{URL: "https://pbs.twimg.com/media/a (1).jpg"}},
+	}
+	wantHTML := `<a href="https://twitter.com/hashtag/caf%C3%A9">#café</a> ` +
+		`<a href="https://en.wikipedia.org/wiki/Go_(language) x">https://t.co/wiki</a>` +
+		`<br><img src="https://pbs.twimg.com/media/a (1).jpg"/>`
+	if got := tweet.RenderHTML(); got != wantHTML {
+		t.Errorf("Unexpected HTML\n got: %s\nwant: %s", got, wantHTML)
+	}
+	wantMarkdown := "[#café](https://twitter.com/hashtag/caf%C3%A9) " +
+		"[en.wikipedia.org/wiki/Go\\_(lang…](https://en.wikipedia.org/wiki/Go_%28language%29%20x)" +
+		"\n\n![](https://pbs.twimg.com/media/a%20%281%29.jpg)"
+	if got := tweet.RenderMarkdown(); got != wantMarkdown {
+		t.Errorf("Unexpected Markdown\n got: %s\nwant: %s", got, wantMarkdown)
+	}
+}
+
+func TestRenderLinksWebURLsOnly(t *testing.T) {
+	tweet := &Tweet{
+		Text: "https://t.co/a https://t.co/b",
+		Entities: []Entity{
+			{Type: EntityURL, Start: 0, End: 14, Text: "https://t.co/a"},
+			{Type: EntityURL, Start: 15, End: 29, Text: "https://t.co/b"},
+		},
+		URLEntities: []URLEntity{{URL: "https://t.co/a"}, {URL: "https://t.co/b"}},
+	}
+	resolver := MapResolver{
+		"https://t.co/a": `javascript:alert("x")`,
+		"https://t.co/b": "HTTPS://example.com/b",
+	}
+	if err := tweet.ResolveURLs(context.Background(), resolver, false); err != nil {
+		t.Fatal(err)
+	}
+	wantHTML := `https://t.co/a <a href="HTTPS://example.com/b">https://t.co/b</a>`
+	if tweet.HTML != wantHTML {
+		t.Errorf("Unexpected HTML\n got: %s\nwant: %s", tweet.HTML, wantHTML)
+	}
+	wantMarkdown := "https://t.co/a [HTTPS://example.com/b](HTTPS://example.com/b)"
+	if got := tweet.RenderMarkdown(); got != wantMarkdown {
+		t.Errorf("Unexpected Markdown\n got: %s\nwant: %s", got, wantMarkdown)
+	}
+}
@@ -0,0 +1,238 @@
+// Package export writes scraped tweets and profiles as CSV or Apache Parquet.
+//
+// Nested fields are flattened into scalar columns. List columns (hashtags,
+// mentions, URLs, media URLs) are joined with Options.Separator, and the tweet
+// Place is split into place_* columns. Rows are written as they are received,
+// so memory use is bounded by a single Parquet row group.
+//
+// The column names listed in TweetColumns and ProfileColumns are part of the
+// package API: existing columns keep their name, type and meaning across
+// releases, and new columns are only ever appended.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+)
+
+// DefaultSeparator joins the values of list columns.
+const DefaultSeparator = "|"
+
+// DefaultRowGroupSize is the number of rows buffered per Parquet row group.
+const DefaultRowGroupSize = 10000
+
+// Options of an exporter.
+type Options struct {
+	// Columns to write, in order. Defaults to TweetColumns or ProfileColumns.
+	Columns []string
+	// Separator joins the values of list columns. Defaults to DefaultSeparator.
+	Separator string
+	// RowGroupSize bounds the rows buffered in memory by Parquet writers.
+	// Defaults to DefaultRowGroupSize.
+	RowGroupSize int
+}
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindBool
+	kindTime
+)
+
+// column extracts a single value from a *Tweet or a *Profile. Values are
+// string, int64, bool or time.Time according to kind.
+type column struct {
+	name  string
+	kind  kind
+	value func(record interface{}, sep string) interface{}
+}
+
+func tweetString(f func(t *twitterscraper.Tweet) string) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} { return f(r.(*twitterscraper.Tweet)) }
+}
+
+func tweetInt(f func(t *twitterscraper.Tweet) int) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} { return int64(f(r.(*twitterscraper.Tweet))) }
+}
+
+func tweetBool(f func(t *twitterscraper.Tweet) bool) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} { return f(r.(*twitterscraper.Tweet)) }
+}
+
+func tweetList(f func(t *twitterscraper.Tweet) []string) func(interface{}, string) interface{} {
+	return func(r interface{}, sep string) interface{} { return strings.Join(f(r.(*twitterscraper.Tweet)), sep) }
+}
+
+func tweetPlace(f func(p *twitterscraper.Place) string) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} {
+		if place := r.(*twitterscraper.Tweet).Place; place != nil {
+			return f(place)
+		}
+		return ""
+	}
+}
+
+func profileString(f func(p *twitterscraper.Profile) string) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} { return f(r.(*twitterscraper.Profile)) }
+}
+
+func profileInt(f func(p *twitterscraper.Profile) int) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} { return int64(f(r.(*twitterscraper.Profile))) }
+}
+
+func profileBool(f func(p *twitterscraper.Profile) bool) func(interface{}, string) interface{} {
+	return func(r interface{}, _ string) interface{} { return f(r.(*twitterscraper.Profile)) }
+}
+
+var tweetColumns = []column{
+	{"id", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.ID })},
+	{"conversation_id", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.ConversationID })},
+	{"created_at", kindTime, func(r interface{}, _ string) interface{} { return r.(*twitterscraper.Tweet).TimeParsed }},
+	{"user_id", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.UserID })},
+	{"username", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.Username })},
+	{"name", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.Name })},
+	{"text", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.Text })},
+	{"permanent_url", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.PermanentURL })},
+	{"likes", kindInt, tweetInt(func(t *twitterscraper.Tweet) int { return t.Likes })},
+	{"replies", kindInt, tweetInt(func(t *twitterscraper.Tweet) int { return t.Replies })},
+	{"retweets", kindInt, tweetInt(func(t *twitterscraper.Tweet) int { return t.Retweets })},
+	{"views", kindInt, tweetInt(func(t *twitterscraper.Tweet) int { return t.Views })},
+	{"is_quoted", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.IsQuoted })},
+	{"is_pin", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.IsPin })},
+	{"is_reply", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.IsReply })},
+	{"is_retweet", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.IsRetweet })},
+	{"is_self_thread", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.IsSelfThread })},
+	{"sensitive_content", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.SensitiveContent })},
+	{"in_reply_to_status_id", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.InReplyToStatusID })},
+	{"quoted_status_id", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.QuotedStatusID })},
+	{"retweeted_status_id", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.RetweetedStatusID })},
+	{"hashtags", kindString, tweetList(func(t *twitterscraper.Tweet) []string { return t.Hashtags })},
+	{"mentions", kindString, tweetList(func(t *twitterscraper.Tweet) []string {
+		var names []string
+		for _, m := range t.Mentions {
+			names = append(names, m.Username)
+		}
+		return names
+	})},
+	{"urls", kindString, tweetList(func(t *twitterscraper.Tweet) []string { return t.URLs })},
+	{"photo_urls", kindString, tweetList(func(t *twitterscraper.Tweet) []string {
+		var urls []string
+		for _, p := range t.Photos {
+			urls = append(urls, p.URL)
+		}
+		return urls
+	})},
+	{"video_urls", kindString, tweetList(func(t *twitterscraper.Tweet) []string {
+		var urls []string
+		for _, v := range t.Videos {
+			urls = append(urls, v.URL)
+		}
+		return urls
+	})},
+	{"gif_urls", kindString, tweetList(func(t *twitterscraper.Tweet) []string {
+		var urls []string
+		for _, g := range t.GIFs {
+			urls = append(urls, g.URL)
+		}
+		return urls
+	})},
+	{"place_id", kindString, tweetPlace(func(p *twitterscraper.Place) string { return p.ID })},
+	{"place_type", kindString, tweetPlace(func(p *twitterscraper.Place) string { return p.PlaceType })},
+	{"place_name", kindString, tweetPlace(func(p *twitterscraper.Place) string { return p.Name })},
+	{"place_full_name", kindString, tweetPlace(func(p *twitterscraper.Place) string { return p.FullName })},
+	{"place_country_code", kindString, tweetPlace(func(p *twitterscraper.Place) string { return p.CountryCode })},
+	{"place_country", kindString, tweetPlace(func(p *twitterscraper.Place) string { return p.Country })},
+	{"lang", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.Lang })},
+	{"source", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.Source })},
+	{"quotes", kindInt, tweetInt(func(t *twitterscraper.Tweet) int { return t.Quotes })},
+	{"bookmarks", kindInt, tweetInt(func(t *twitterscraper.Tweet) int { return t.Bookmarks })},
+	{"possibly_sensitive", kindBool, tweetBool(func(t *twitterscraper.Tweet) bool { return t.PossiblySensitive })},
+	{"reply_settings", kindString, tweetString(func(t *twitterscraper.Tweet) string { return t.ReplySettings })},
+	{"cashtags", kindString, tweetList(func(t *twitterscraper.Tweet) []string { return t.Cashtags })},
+}
+
+var profileColumns = []column{
+	{"user_id", kindString, profileString(func(p *twitterscraper.Profile) string { return p.UserID })},
+	{"username", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Username })},
+	{"name", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Name })},
+	{"biography", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Biography })},
+	{"location", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Location })},
+	{"website", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Website })},
+	{"url", kindString, profileString(func(p *twitterscraper.Profile) string { return p.URL })},
+	{"avatar", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Avatar })},
+	{"banner", kindString, profileString(func(p *twitterscraper.Profile) string { return p.Banner })},
+	{"joined", kindTime, func(r interface{}, _ string) interface{} {
+		if joined := r.(*twitterscraper.Profile).Joined; joined != nil {
+			return *joined
+		}
+		return time.Time{}
+	}},
+	{"followers_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.FollowersCount })},
+	{"following_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.FollowingCount })},
+	{"friends_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.FriendsCount })},
+	{"likes_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.LikesCount })},
+	{"listed_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.ListedCount })},
+	{"tweets_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.TweetsCount })},
+	{"is_private", kindBool, profileBool(func(p *twitterscraper.Profile) bool { return p.IsPrivate })},
+	{"is_verified", kindBool, profileBool(func(p *twitterscraper.Profile) bool { return p.IsVerified })},
+	{"pinned_tweet_ids", kindString, func(r interface{}, sep string) interface{} {
+		return strings.Join(r.(*twitterscraper.Profile).PinnedTweetIDs, sep)
+	}},
+	{"is_blue_verified", kindBool, profileBool(func(p *twitterscraper.Profile) bool { return p.IsBlueVerified })},
+	{"verified_type", kindString, profileString(func(p *twitterscraper.Profile) string { return p.VerifiedType })},
+	{"media_count", kindInt, profileInt(func(p *twitterscraper.Profile) int { return p.MediaCount })},
+	{"professional_category", kindString, profileString(func(p *twitterscraper.Profile) string { return p.ProfessionalCategory })},
+}
+
+// TweetColumns lists every tweet column in default order.
+var TweetColumns = columnNames(tweetColumns)
+
+// ProfileColumns lists every profile column in default order.
+var ProfileColumns = columnNames(profileColumns)
+
+func columnNames(columns []column) []string {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// selectColumns resolves names against the available columns.
+func selectColumns(available []column, names []string) ([]column, error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+	selected := make([]column, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("export: duplicate column %q", name)
+		}
+		seen[name] = true
+		found := false
+		for _, c := range available {
+			if c.name == name {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("export: unknown column %q", name)
+		}
+	}
+	return selected, nil
+}
+
+func (opts Options) separator() string {
+	if opts.Separator == "" {
+		return DefaultSeparator
+	}
+	return opts.Separator
+}
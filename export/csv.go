@@ -0,0 +1,112 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+)
+
+// csvEncoder writes records as CSV rows with a header line.
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []column
+	sep     string
+	record  []string
+}
+
+func newCSVEncoder(w io.Writer, available []column, opts Options) (*csvEncoder, error) {
+	columns, err := selectColumns(available, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+	enc := &csvEncoder{
+		w:       csv.NewWriter(w),
+		columns: columns,
+		sep:     opts.separator(),
+		record:  make([]string, len(columns)),
+	}
+	if err := enc.w.Write(columnNames(columns)); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+func (enc *csvEncoder) write(record interface{}) error {
+	for i, c := range enc.columns {
+		enc.record[i] = formatCSV(c.value(record, enc.sep))
+	}
+	return enc.w.Write(enc.record)
+}
+
+func (enc *csvEncoder) close() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+func formatCSV(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+// TweetCSVWriter writes tweets as CSV rows.
+type TweetCSVWriter struct {
+	enc *csvEncoder
+}
+
+// NewTweetCSVWriter writes the CSV header and returns a writer for tweets.
+func NewTweetCSVWriter(w io.Writer, opts Options) (*TweetCSVWriter, error) {
+	enc, err := newCSVEncoder(w, tweetColumns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TweetCSVWriter{enc}, nil
+}
+
+// Write a single tweet.
+func (w *TweetCSVWriter) Write(tweet *twitterscraper.Tweet) error {
+	return w.enc.write(tweet)
+}
+
+// Close flushes buffered rows. It does not close the underlying io.Writer.
+func (w *TweetCSVWriter) Close() error {
+	return w.enc.close()
+}
+
+// ProfileCSVWriter writes profiles as CSV rows.
+type ProfileCSVWriter struct {
+	enc *csvEncoder
+}
+
+// NewProfileCSVWriter writes the CSV header and returns a writer for profiles.
+func NewProfileCSVWriter(w io.Writer, opts Options) (*ProfileCSVWriter, error) {
+	enc, err := newCSVEncoder(w, profileColumns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileCSVWriter{enc}, nil
+}
+
+// Write a single profile.
+func (w *ProfileCSVWriter) Write(profile *twitterscraper.Profile) error {
+	return w.enc.write(profile)
+}
+
+// Close flushes buffered rows. It does not close the underlying io.Writer.
+func (w *ProfileCSVWriter) Close() error {
+	return w.enc.close()
+}
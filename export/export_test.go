@@ -0,0 +1,208 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/masa-finance/masa-twitter-scraper/export"
+	"github.com/parquet-go/parquet-go"
+)
+
+func tweetResults(results ...*twitterscraper.TweetResult) <-chan *twitterscraper.TweetResult {
+	ch := make(chan *twitterscraper.TweetResult, len(results))
+	for _, r := range results {
+		ch <- r
+	}
+	close(ch)
+	return ch
+}
+
+var sampleTweet = twitterscraper.Tweet{
+	ID:         "1390026628957417473",
+	Hashtags:   []string{"a", "b"},
+	Likes:      42,
+	Mentions:   []twitterscraper.Mention{{ID: "1", Username: "one"}, {ID: "2", Username: "two"}},
+	Photos:     []twitterscraper.Photo{{ID: "3", URL: "https://pbs.twimg.com/media/E0pd2L2XEAQ_gnn.jpg"}},
+	Place:      &twitterscraper.Place{ID: "p1", FullName: "Kyiv, Ukraine", CountryCode: "UA"},
+	Text:       "hello, \"world\"\nsecond line",
+	TimeParsed: time.Date(2021, 5, 5, 19, 32, 28, 0, time.UTC),
+	Timestamp:  1620243148,
+	Username:   "Twitter",
+}
+
+func TestWriteTweetsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := export.WriteTweetsCSV(&buf, tweetResults(&twitterscraper.TweetResult{Tweet: sampleTweet}), export.Options{
+		Columns: []string{"id", "created_at", "text", "likes", "hashtags", "mentions", "photo_urls", "place_full_name", "video_urls"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 row written, got %d", n)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"id", "created_at", "text", "likes", "hashtags", "mentions", "photo_urls", "place_full_name", "video_urls"},
+		{"1390026628957417473", "2021-05-05T19:32:28Z", sampleTweet.Text, "42", "a|b", "one|two", "https://pbs.twimg.com/media/E0pd2L2XEAQ_gnn.jpg", "Kyiv, Ukraine", ""},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Error("CSV output does not match", diff)
+	}
+}
+
+func TestWriteCSVStopsOnError(t *testing.T) {
+	var buf bytes.Buffer
+	fail := errors.New("scrape failed")
+	n, err := export.WriteTweetsCSV(&buf, tweetResults(
+		&twitterscraper.TweetResult{Tweet: sampleTweet},
+		&twitterscraper.TweetResult{Error: fail},
+	), export.Options{})
+	if err != fail {
+		t.Errorf("Expected result error, got %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 row written, got %d", n)
+	}
+}
+
+func TestUnknownColumn(t *testing.T) {
+	if _, err := export.NewTweetCSVWriter(io.Discard, export.Options{Columns: []string{"nope"}}); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+func TestWriteTweetsParquet(t *testing.T) {
+	var buf bytes.Buffer
+	second := sampleTweet
+	second.ID = "2"
+	second.Place = nil
+	second.TimeParsed = time.Time{}
+	n, err := export.WriteTweetsParquet(&buf, tweetResults(
+		&twitterscraper.TweetResult{Tweet: sampleTweet},
+		&twitterscraper.TweetResult{Tweet: second},
+	), export.Options{RowGroupSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 rows written, got %d", n)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.NumRows() != 2 {
+		t.Errorf("Expected 2 rows, got %d", file.NumRows())
+	}
+	if len(file.RowGroups()) != 2 {
+		t.Errorf("Expected 2 row groups, got %d", len(file.RowGroups()))
+	}
+
+	columns := make(map[string]int)
+	for i, path := range file.Schema().Columns() {
+		columns[path[0]] = i
+	}
+	if len(columns) != len(export.TweetColumns) {
+		t.Errorf("Expected %d columns, got %d", len(export.TweetColumns), len(columns))
+	}
+
+	reader := parquet.NewReader(file)
+	var rows []parquet.Row
+	for {
+		row := make([]parquet.Row, 1)
+		n, err := reader.ReadRows(row)
+		for _, r := range row[:n] {
+			rows = append(rows, r.Clone())
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows read, got %d", len(rows))
+	}
+	if got := rows[0][columns["id"]].String(); got != sampleTweet.ID {
+		t.Errorf("Expected id %s, got %s", sampleTweet.ID, got)
+	}
+	if got := rows[0][columns["likes"]].Int64(); got != 42 {
+		t.Errorf("Expected likes 42, got %d", got)
+	}
+	if got := rows[0][columns["hashtags"]].String(); got != "a|b" {
+		t.Errorf("Expected hashtags a|b, got %s", got)
+	}
+	if got := rows[0][columns["created_at"]].Int64(); got != sampleTweet.TimeParsed.UnixMilli() {
+		t.Errorf("Expected created_at %d, got %d", sampleTweet.TimeParsed.UnixMilli(), got)
+	}
+	if !rows[1][columns["place_full_name"]].IsNull() {
+		t.Error("Expected place_full_name is null")
+	}
+	if !rows[1][columns["created_at"]].IsNull() {
+		t.Error("Expected created_at is null")
+	}
+}
+
+func TestWriteTweetsParquetColumnOrder(t *testing.T) {
+	var buf bytes.Buffer
+	order := []string{"likes", "id", "created_at", "hashtags"}
+	_, err := export.WriteTweetsParquet(&buf, tweetResults(&twitterscraper.TweetResult{Tweet: sampleTweet}), export.Options{Columns: order})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, path := range file.Schema().Columns() {
+		got = append(got, path[0])
+	}
+	if diff := cmp.Diff(order, got); diff != "" {
+		t.Error("Parquet columns are not in the requested order", diff)
+	}
+
+	rows := make([]parquet.Row, 1)
+	if n, _ := parquet.NewReader(file).ReadRows(rows); n != 1 {
+		t.Fatalf("Expected 1 row, got %d", n)
+	}
+	if likes, id := rows[0][0].Int64(), rows[0][1].String(); likes != 42 || id != sampleTweet.ID {
+		t.Errorf("Unexpected row %v", rows[0])
+	}
+}
+
+func TestWriteProfilesCSV(t *testing.T) {
+	joined := time.Date(2010, 1, 18, 8, 49, 30, 0, time.UTC)
+	profiles := make(chan *twitterscraper.ProfileResult, 1)
+	profiles <- &twitterscraper.ProfileResult{Profile: twitterscraper.Profile{
+		UserID:         "106037940",
+		Username:       "nomadic_ua",
+		Joined:         &joined,
+		FollowersCount: 7,
+		PinnedTweetIDs: []string{"1", "2"},
+	}}
+	close(profiles)
+
+	var buf bytes.Buffer
+	if _, err := export.WriteProfilesCSV(&buf, profiles, export.Options{
+		Columns:   []string{"username", "joined", "followers_count", "pinned_tweet_ids"},
+		Separator: ";",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "username,joined,followers_count,pinned_tweet_ids\nnomadic_ua,2010-01-18T08:49:30Z,7,1;2\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
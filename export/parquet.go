@@ -0,0 +1,161 @@
+package export
+
+import (
+	"io"
+	"reflect"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetEncoder writes records as Parquet rows. String and timestamp columns
+// are optional and hold null when empty; integer and boolean columns are
+// required.
+type parquetEncoder struct {
+	w       *parquet.Writer
+	columns []column
+	sep     string
+	row     parquet.Row
+}
+
+// orderedGroup is a parquet.Group whose fields keep the order of names,
+// where parquet.Group sorts them by name.
+type orderedGroup struct {
+	parquet.Group
+	names []string
+}
+
+func (g orderedGroup) Fields() []parquet.Field {
+	fields := make([]parquet.Field, len(g.names))
+	for i, name := range g.names {
+		fields[i] = orderedField{Node: g.Group[name], name: name}
+	}
+	return fields
+}
+
+type orderedField struct {
+	parquet.Node
+	name string
+}
+
+func (f orderedField) Name() string { return f.name }
+
+func (f orderedField) Value(base reflect.Value) reflect.Value {
+	return base.MapIndex(reflect.ValueOf(&f.name).Elem())
+}
+
+func newParquetEncoder(w io.Writer, name string, available []column, opts Options) (*parquetEncoder, error) {
+	columns, err := selectColumns(available, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+	group := orderedGroup{Group: parquet.Group{}}
+	for _, c := range columns {
+		group.names = append(group.names, c.name)
+		switch c.kind {
+		case kindString:
+			group.Group[c.name] = parquet.Optional(parquet.String())
+		case kindInt:
+			group.Group[c.name] = parquet.Int(64)
+		case kindBool:
+			group.Group[c.name] = parquet.Leaf(parquet.BooleanType)
+		case kindTime:
+			group.Group[c.name] = parquet.Optional(parquet.Timestamp(parquet.Millisecond))
+		}
+	}
+	schema := parquet.NewSchema(name, group)
+
+	rowGroupSize := opts.RowGroupSize
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	return &parquetEncoder{
+		w:       parquet.NewWriter(w, schema, parquet.MaxRowsPerRowGroup(int64(rowGroupSize))),
+		columns: columns,
+		sep:     opts.separator(),
+		row:     make(parquet.Row, len(columns)),
+	}, nil
+}
+
+func (enc *parquetEncoder) write(record interface{}) error {
+	for leaf, c := range enc.columns {
+		var value parquet.Value
+		definitionLevel := 0
+		switch v := c.value(record, enc.sep).(type) {
+		case string:
+			if v != "" {
+				value = parquet.ByteArrayValue([]byte(v))
+				definitionLevel = 1
+			}
+		case int64:
+			value = parquet.Int64Value(v)
+		case bool:
+			value = parquet.BooleanValue(v)
+		case time.Time:
+			if !v.IsZero() {
+				value = parquet.Int64Value(v.UnixMilli())
+				definitionLevel = 1
+			}
+		}
+		enc.row[leaf] = value.Level(0, definitionLevel, leaf)
+	}
+	_, err := enc.w.WriteRows([]parquet.Row{enc.row})
+	return err
+}
+
+func (enc *parquetEncoder) close() error {
+	return enc.w.Close()
+}
+
+// TweetParquetWriter writes tweets to a Parquet file.
+type TweetParquetWriter struct {
+	enc *parquetEncoder
+}
+
+// NewTweetParquetWriter returns a writer for tweets.
+func NewTweetParquetWriter(w io.Writer, opts Options) (*TweetParquetWriter, error) {
+	enc, err := newParquetEncoder(w, "tweet", tweetColumns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TweetParquetWriter{enc}, nil
+}
+
+// Write a single tweet.
+func (w *TweetParquetWriter) Write(tweet *twitterscraper.Tweet) error {
+	return w.enc.write(tweet)
+}
+
+// Close flushes the last row group and writes the file footer. It must be
+// called for the file to be readable. It does not close the underlying
+// io.Writer.
+func (w *TweetParquetWriter) Close() error {
+	return w.enc.close()
+}
+
+// ProfileParquetWriter writes profiles to a Parquet file.
+type ProfileParquetWriter struct {
+	enc *parquetEncoder
+}
+
+// NewProfileParquetWriter returns a writer for profiles.
+func NewProfileParquetWriter(w io.Writer, opts Options) (*ProfileParquetWriter, error) {
+	enc, err := newParquetEncoder(w, "profile", profileColumns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileParquetWriter{enc}, nil
+}
+
+// Write a single profile.
+func (w *ProfileParquetWriter) Write(profile *twitterscraper.Profile) error {
+	return w.enc.write(profile)
+}
+
+// Close flushes the last row group and writes the file footer. It must be
+// called for the file to be readable. It does not close the underlying
+// io.Writer.
+func (w *ProfileParquetWriter) Close() error {
+	return w.enc.close()
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"io"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+)
+
+// encoder is implemented by the CSV and Parquet encoders.
+type encoder interface {
+	write(record interface{}) error
+	close() error
+}
+
+// WriteTweetsCSV writes every tweet received from tweets as CSV and returns
+// the number of rows written. It stops at the first result error, after
+// flushing the rows written so far; cancel the scraping context to stop the
+// producer in that case.
+func WriteTweetsCSV(w io.Writer, tweets <-chan *twitterscraper.TweetResult, opts Options) (int, error) {
+	enc, err := newCSVEncoder(w, tweetColumns, opts)
+	if err != nil {
+		return 0, err
+	}
+	return writeTweets(enc, tweets)
+}
+
+// WriteTweetsParquet is like WriteTweetsCSV, writing a Parquet file.
+func WriteTweetsParquet(w io.Writer, tweets <-chan *twitterscraper.TweetResult, opts Options) (int, error) {
+	enc, err := newParquetEncoder(w, "tweet", tweetColumns, opts)
+	if err != nil {
+		return 0, err
+	}
+	return writeTweets(enc, tweets)
+}
+
+// WriteProfilesCSV writes every profile received from profiles as CSV and
+// returns the number of rows written. It stops at the first result error,
+// after flushing the rows written so far.
+func WriteProfilesCSV(w io.Writer, profiles <-chan *twitterscraper.ProfileResult, opts Options) (int, error) {
+	enc, err := newCSVEncoder(w, profileColumns, opts)
+	if err != nil {
+		return 0, err
+	}
+	return writeProfiles(enc, profiles)
+}
+
+// WriteProfilesParquet is like WriteProfilesCSV, writing a Parquet file.
+func WriteProfilesParquet(w io.Writer, profiles <-chan *twitterscraper.ProfileResult, opts Options) (int, error) {
+	enc, err := newParquetEncoder(w, "profile", profileColumns, opts)
+	if err != nil {
+		return 0, err
+	}
+	return writeProfiles(enc, profiles)
+}
+
+func writeTweets(enc encoder, tweets <-chan *twitterscraper.TweetResult) (int, error) {
+	n := 0
+	for tweet := range tweets {
+		if tweet.Error != nil {
+			return n, closeWithError(enc, tweet.Error)
+		}
+		if err := enc.write(&tweet.Tweet); err != nil {
+			return n, closeWithError(enc, err)
+		}
+		n++
+	}
+	return n, enc.close()
+}
+
+func writeProfiles(enc encoder, profiles <-chan *twitterscraper.ProfileResult) (int, error) {
+	n := 0
+	for profile := range profiles {
+		if profile.Error != nil {
+			return n, closeWithError(enc, profile.Error)
+		}
+		if err := enc.write(&profile.Profile); err != nil {
+			return n, closeWithError(enc, err)
+		}
+		n++
+	}
+	return n, enc.close()
+}
+
+func closeWithError(enc encoder, err error) error {
+	enc.close()
+	return err
+}
@@ -1,10 +1,15 @@
 package twitterscraper
 
 import (
+	"context"
 	"log"
 	"net/url"
 )
 
+// Response is the response of the Followers operation.
+//
+// Deprecated: Response is only returned through FetchFollowers. Use
+// FetchFollowerProfiles, which maps followers to profiles.
 type Response struct {
 	Data struct {
 		User struct {
@@ -31,6 +36,10 @@ type Response struct {
 	} `json:"data"`
 }
 
+// Legacy is a follower as returned by the API.
+//
+// Deprecated: Legacy is only returned by FetchFollowers. Use
+// FetchFollowerProfiles, which returns every counter of Legacy in Profile.
 type Legacy struct {
 	CanDM                bool     `json:"can_dm"`
 	CanMediaTag          bool     `json:"can_media_tag"`
@@ -63,19 +72,23 @@ type Legacy struct {
 	WithheldInCountries  []string `json:"withheld_in_countries"`
 }
 
+// Deprecated: Entities is only used by Legacy.
 type Entities struct {
 	Description Description `json:"description"`
 	Url         Url         `json:"url"`
 }
 
+// Deprecated: Description is only used by Legacy.
 type Description struct {
 	Urls []UrlInfo `json:"urls"`
 }
 
+// Deprecated: Url is only used by Legacy.
 type Url struct {
 	Urls []UrlInfo `json:"urls"`
 }
 
+// Deprecated: UrlInfo is only used by Legacy.
 type UrlInfo struct {
 	DisplayUrl  string `json:"display_url"`
 	ExpandedUrl string `json:"expanded_url"`
@@ -83,22 +96,58 @@ type UrlInfo struct {
 	Indices     []int  `json:"indices"`
 }
 
+// GetFollowers returns channel with profiles of users following a given user.
+func (s *Scraper) GetFollowers(ctx context.Context, username string, maxProfilesNbr int) <-chan *ProfileResult {
+	return getUserTimeline(ctx, username, maxProfilesNbr, s.FetchFollowerProfiles)
+}
+
+// FetchFollowerProfiles gets the profiles of the followers of a given user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchFollowerProfiles(username string, maxProfilesNbr int, cursor string) ([]*Profile, string, error) {
+	var timeline timelineV2
+	if err := s.requestFollowers(username, maxProfilesNbr, cursor, &timeline); err != nil {
+		return nil, "", err
+	}
+	profiles, nextCursor := parseTimelineUsers(timeline.instructions())
+	return profiles, nextCursor, nil
+}
+
 // FetchFollowers gets the list of followers for a given user, via the Twitter frontend GraphQL API.
+//
+// Deprecated: FetchFollowers returns the raw API objects and no cursor. Use
+// FetchFollowerProfiles or GetFollowers.
 func (s *Scraper) FetchFollowers(username string, maxUsersNbr int, cursor string) ([]Legacy, string, error) {
+	var response Response
+	err := s.requestFollowers(username, maxUsersNbr, cursor, &response)
+	if err != nil {
+		// Handle the error, for example, log it or return it to the caller
+		log.Printf("Error making API request: %v", err)
+		return nil, "", err
+	}
+
+	legacies, nextCursor, err := response.parseFollowing()
+	if err != nil {
+		// Handle the parsing error
+		log.Printf("Error parsing following response: %v", err)
+		return nil, "", err
+	}
+
+	// If err is nil here, it means both the API request and parsing were successful
+	return legacies, nextCursor, nil
+}
+
+func (s *Scraper) requestFollowers(username string, maxUsersNbr int, cursor string, target interface{}) error {
 	if maxUsersNbr > 200 {
 		maxUsersNbr = 200
 	}
 
-	// Use GetProfile to get the userID from the username
-	profile, err := s.GetProfile(username)
+	userID, err := s.GetUserIDByScreenName(username)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
-	userID := profile.UserID
 
 	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/o1YfmoGa-hb8Z6yQhoIBhg/Followers")
 	if err != nil {
-		return nil, "", err
+		return err
 	}
 
 	variables := map[string]interface{}{
@@ -117,23 +166,7 @@ func (s *Scraper) FetchFollowers(username string, maxUsersNbr int, cursor string
 	query.Set("features", mapToJSONString(features))
 	req.URL.RawQuery = query.Encode()
 
-	var response Response
-	err = s.RequestAPI(req, &response)
-	if err != nil {
-		// Handle the error, for example, log it or return it to the caller
-		log.Printf("Error making API request: %v", err)
-		return nil, "", err
-	}
-
-	legacies, nextCursor, err := response.parseFollowing()
-	if err != nil {
-		// Handle the parsing error
-		log.Printf("Error parsing following response: %v", err)
-		return nil, "", err
-	}
-
-	// If err is nil here, it means both the API request and parsing were successful
-	return legacies, nextCursor, nil
+	return s.RequestAPI(req, target)
 }
 
 func (fr Response) parseFollowing() ([]Legacy, string, error) {
@@ -0,0 +1,54 @@
+package twitterscraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newFakeScraper returns a logged in Scraper whose requests are answered by
+// handler with a JSON response.
+func newFakeScraper(t *testing.T, handler func(req *http.Request) interface{}) *Scraper {
+	s := New()
+	s.isLogged = true
+	s.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := json.Marshal(handler(req))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	return s
+}
+
+// tweetResultJSON encodes a tweet posted by user as a GraphQL tweet result.
+func tweetResultJSON(id, user string) map[string]interface{} {
+	return map[string]interface{}{
+		"__typename": "Tweet",
+		"rest_id":    id,
+		"core":       map[string]interface{}{"user_results": map[string]interface{}{"result": userJSON("id-"+user, user)}},
+		"legacy":     map[string]interface{}{"id_str": id, "user_id_str": "id-" + user, "full_text": "tweet " + id},
+	}
+}
+
+// userJSON encodes a user as a GraphQL user result.
+func userJSON(id, screenName string) map[string]interface{} {
+	return map[string]interface{}{
+		"__typename": "User",
+		"rest_id":    id,
+		"legacy":     map[string]interface{}{"screen_name": screenName, "name": screenName},
+	}
+}
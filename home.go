@@ -0,0 +1,100 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/url"
+)
+
+type homeTimeline struct {
+	Data struct {
+		Home struct {
+			HomeTimelineURT struct {
+				Instructions []instruction `json:"instructions"`
+			} `json:"home_timeline_urt"`
+		} `json:"home"`
+	} `json:"data"`
+}
+
+// GetHomeTimeline returns channel with tweets of the home timeline of the
+// logged in user: the "For you" tab, or the "Following" tab if latest is true.
+// Requires a session logged in with a user account.
+func (s *Scraper) GetHomeTimeline(ctx context.Context, maxTweetsNbr int, latest bool) <-chan *TweetResult {
+	return getTweetTimeline(ctx, "", maxTweetsNbr, func(_ string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+		return s.FetchHomeTimeline(maxTweetsNbr, cursor, latest)
+	})
+}
+
+// FetchHomeTimeline gets tweets of the home timeline of the logged in user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchHomeTimeline(maxTweetsNbr int, cursor string, latest bool) ([]*Tweet, string, error) {
+	if err := s.requireUserSession(); err != nil {
+		return nil, "", err
+	}
+	if maxTweetsNbr > 100 {
+		maxTweetsNbr = 100
+	}
+
+	endpoint := "https://twitter.com/i/api/graphql/HCosKfLNW1AcOo3la3mMgg/HomeTimeline"
+	if latest {
+		endpoint = "https://twitter.com/i/api/graphql/DiTkXJgLqBBxCs7zaYsbtA/HomeLatestTimeline"
+	}
+	req, err := s.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	variables := map[string]interface{}{
+		"count":                  maxTweetsNbr,
+		"includePromotedContent": !s.skipPromoted,
+		"latestControlAvailable": true,
+		"requestContext":         "launch",
+		"withCommunity":          true,
+	}
+	features := map[string]interface{}{
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var timeline homeTimeline
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+
+	instructions := timeline.Data.Home.HomeTimelineURT.Instructions
+	if s.skipPromoted {
+		instructions = withoutPromoted(instructions)
+	}
+	tweets, nextCursor := parseTimelineTweets(instructions)
+	return tweets, nextCursor, nil
+}
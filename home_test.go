@@ -0,0 +1,71 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetHomeTimeline(t *testing.T) {
+	tweetEntry := func(entryID, id string, promoted bool) map[string]interface{} {
+		content := tweetItem(id)["item"].(map[string]interface{})
+		if promoted {
+			content["itemContent"].(map[string]interface{})["promotedMetadata"] = map[string]interface{}{"impressionId": "x"}
+		}
+		return map[string]interface{}{"entryId": entryID, "content": content}
+	}
+	promotedItem := func(item map[string]interface{}) map[string]interface{} {
+		content := item["item"].(map[string]interface{})["itemContent"].(map[string]interface{})
+		content["promotedMetadata"] = map[string]interface{}{"impressionId": "x"}
+		return item
+	}
+	var paths []string
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		paths = append(paths, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+		if cursor, _ := requestVariables(t, req)["cursor"].(string); cursor != "" {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"home": map[string]interface{}{
+			"home_timeline_urt": map[string]interface{}{"instructions": []interface{}{
+				map[string]interface{}{"type": "TimelineAddEntries", "entries": []interface{}{
+					tweetEntry("tweet-1", "1", false),
+					tweetEntry("promoted-tweet-2-abc", "2", false),
+					tweetEntry("tweet-3", "3", true),
+					tweetEntry("tweet-4", "4", false),
+					map[string]interface{}{"entryId": "home-conversation-5", "content": map[string]interface{}{"items": []interface{}{
+						tweetItem("5"),
+						promotedItem(tweetItem("6")),
+					}}},
+					bottomCursor("c1"),
+				}},
+				map[string]interface{}{"type": "TimelineAddToModule", "moduleEntryId": "home-conversation-5", "moduleItems": []interface{}{
+					promotedItem(tweetItem("7")),
+				}},
+			}},
+		}}}
+	})
+
+	collect := func(latest bool) []string {
+		var ids []string
+		for result := range scraper.GetHomeTimeline(context.Background(), 10, latest) {
+			if result.Error != nil {
+				t.Fatal(result.Error)
+			}
+			ids = append(ids, result.ID)
+		}
+		return ids
+	}
+	if diff := cmp.Diff([]string{"1", "2", "3", "4", "5", "6", "7"}, collect(false)); diff != "" {
+		t.Error("Home timeline does not match", diff)
+	}
+	scraper.WithSkipPromoted(true)
+	if diff := cmp.Diff([]string{"1", "4", "5"}, collect(true)); diff != "" {
+		t.Error("Home timeline without promoted tweets does not match", diff)
+	}
+	if diff := cmp.Diff([]string{"HomeTimeline", "HomeTimeline", "HomeLatestTimeline", "HomeLatestTimeline"}, paths); diff != "" {
+		t.Error("Requested operations do not match", diff)
+	}
+}
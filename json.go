@@ -0,0 +1,154 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SchemaVersion of the JSON encoding of Tweet and Profile. It is written to
+// the "schema_version" field and is increased on incompatible changes only;
+// new optional fields may be added within a version.
+//
+// Version 2 fixed the "following_count" of profiles, which held the likes
+// count in version 1, and encodes the duration of videos in milliseconds as
+// "duration_ms".
+const SchemaVersion = 2
+
+type (
+	tweetAlias   Tweet
+	profileAlias Profile
+	videoAlias   Video
+
+	tweetJSON struct {
+		SchemaVersion int `json:"schema_version"`
+		*tweetAlias
+		ThreadIDs        []string          `json:"thread_ids,omitempty"`
+		ReferencedTweets map[string]*Tweet `json:"referenced_tweets,omitempty"`
+	}
+
+	profileJSON struct {
+		SchemaVersion int `json:"schema_version"`
+		*profileAlias
+	}
+
+	videoJSON struct {
+		*videoAlias
+		DurationMs int64 `json:"duration_ms,omitempty"`
+	}
+)
+
+// MarshalJSON encodes the tweet with snake_case field names.
+//
+// The creation time is stored once, as the Unix "timestamp"; TimeParsed is
+// restored from it when decoding. Nested tweets are stored as ID references
+// only ("in_reply_to_status_id", "quoted_status_id", "retweeted_status_id"
+// and "thread_ids"), so the output never nests. Use MarshalTweetEmbedded to
+// include the referenced tweets. Empty media, entity and place fields are
+// omitted.
+func (tweet Tweet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newTweetJSON(&tweet))
+}
+
+// MarshalTweetEmbedded encodes the tweet like MarshalJSON and additionally
+// embeds the tweets it references under "referenced_tweets", keyed by ID.
+// Embedding is one level deep: referenced tweets carry ID references only.
+func MarshalTweetEmbedded(tweet *Tweet) ([]byte, error) {
+	jsn := newTweetJSON(tweet)
+	refs := []*Tweet{tweet.InReplyToStatus, tweet.QuotedStatus, tweet.RetweetedStatus}
+	refs = append(refs, tweet.Thread...)
+	for _, ref := range refs {
+		if ref == nil || ref.ID == "" {
+			continue
+		}
+		if jsn.ReferencedTweets == nil {
+			jsn.ReferencedTweets = make(map[string]*Tweet)
+		}
+		jsn.ReferencedTweets[ref.ID] = ref
+	}
+	return json.Marshal(jsn)
+}
+
+func newTweetJSON(tweet *Tweet) *tweetJSON {
+	jsn := &tweetJSON{
+		SchemaVersion: SchemaVersion,
+		tweetAlias:    (*tweetAlias)(tweet),
+	}
+	for _, t := range tweet.Thread {
+		jsn.ThreadIDs = append(jsn.ThreadIDs, t.ID)
+	}
+	return jsn
+}
+
+// UnmarshalJSON decodes a tweet encoded by MarshalJSON or
+// MarshalTweetEmbedded. Referenced tweets are linked when they were embedded;
+// thread entries that were not embedded are restored with their ID only.
+func (tweet *Tweet) UnmarshalJSON(data []byte) error {
+	jsn := tweetJSON{tweetAlias: (*tweetAlias)(tweet)}
+	if err := json.Unmarshal(data, &jsn); err != nil {
+		return err
+	}
+	tweet.setTimeParsed()
+
+	refs := jsn.ReferencedTweets
+	tweet.InReplyToStatus = refs[tweet.InReplyToStatusID]
+	tweet.QuotedStatus = refs[tweet.QuotedStatusID]
+	tweet.RetweetedStatus = refs[tweet.RetweetedStatusID]
+	tweet.Thread = nil
+	for _, id := range jsn.ThreadIDs {
+		t, ok := refs[id]
+		if !ok || t == nil {
+			t = &Tweet{ID: id}
+		}
+		tweet.Thread = append(tweet.Thread, t)
+	}
+	return nil
+}
+
+func (tweet *Tweet) setTimeParsed() {
+	tweet.TimeParsed = time.Time{}
+	if tweet.Timestamp != 0 {
+		tweet.TimeParsed = time.Unix(tweet.Timestamp, 0).UTC()
+	}
+}
+
+// MarshalJSON encodes the profile with snake_case field names and the
+// "schema_version" field.
+func (profile Profile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&profileJSON{
+		SchemaVersion: SchemaVersion,
+		profileAlias:  (*profileAlias)(&profile),
+	})
+}
+
+// UnmarshalJSON decodes a profile encoded by MarshalJSON. The
+// FollowingCount of profiles encoded with schema version 1 is restored from
+// FriendsCount; documents without a schema version are decoded as is.
+func (profile *Profile) UnmarshalJSON(data []byte) error {
+	jsn := profileJSON{profileAlias: (*profileAlias)(profile)}
+	if err := json.Unmarshal(data, &jsn); err != nil {
+		return err
+	}
+	if jsn.SchemaVersion == 1 {
+		profile.FollowingCount = profile.FriendsCount
+	}
+	return nil
+}
+
+// MarshalJSON encodes the video with its duration in milliseconds, as
+// "duration_ms".
+func (video Video) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&videoJSON{
+		videoAlias: (*videoAlias)(&video),
+		DurationMs: video.Duration.Milliseconds(),
+	})
+}
+
+// UnmarshalJSON decodes a video encoded by MarshalJSON.
+func (video *Video) UnmarshalJSON(data []byte) error {
+	jsn := videoJSON{videoAlias: (*videoAlias)(video)}
+	if err := json.Unmarshal(data, &jsn); err != nil {
+		return err
+	}
+	video.Duration = time.Duration(jsn.DurationMs) * time.Millisecond
+	return nil
+}
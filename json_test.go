@@ -0,0 +1,161 @@
+package twitterscraper_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+)
+
+func sampleTweet() *twitterscraper.Tweet {
+	parent := &twitterscraper.Tweet{
+		ID:         "1",
+		Text:       "parent",
+		TimeParsed: time.Date(2021, 5, 5, 19, 32, 28, 0, time.UTC),
+		Timestamp:  1620243148,
+		UserID:     "783214",
+		Username:   "Twitter",
+	}
+	tweet := &twitterscraper.Tweet{
+		ConversationID:    "1",
+		Hashtags:          []string{"golang"},
+		ID:                "2",
+		InReplyToStatus:   parent,
+		InReplyToStatusID: "1",
+		IsReply:           true,
+		IsSelfThread:      true,
+		Likes:             10,
+		Mentions:          []twitterscraper.Mention{{ID: "7018222", Username: "davidmcraney", Name: "David McRaney"}},
+		PermanentURL:      "https://twitter.com/Twitter/status/2",
+		Photos:            []twitterscraper.Photo{{ID: "3", URL: "https://pbs.twimg.com/media/E0pd2L2XEAQ_gnn.jpg"}},
+		Text:              "reply #golang",
+		TimeParsed:        time.Date(2021, 5, 5, 19, 40, 0, 0, time.UTC),
+		Timestamp:         1620243600,
+		UserID:            "783214",
+		Username:          "Twitter",
+		Views:             100,
+	}
+	tweet.Thread = []*twitterscraper.Tweet{parent}
+	parent.Thread = []*twitterscraper.Tweet{tweet}
+	return tweet
+}
+
+func TestTweetJSONRoundTrip(t *testing.T) {
+	tweet := sampleTweet()
+
+	data, err := json.Marshal(tweet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"schema_version":2`, `"in_reply_to_status_id":"1"`, `"thread_ids":["1"]`, `"timestamp":1620243600`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("Expected %s in %s", field, data)
+		}
+	}
+	for _, field := range []string{"TimeParsed", "time_parsed", "videos", "gifs", "place", "referenced_tweets"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("Unexpected %s in %s", field, data)
+		}
+	}
+
+	var decoded twitterscraper.Tweet
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	expected := *tweet
+	expected.InReplyToStatus = nil
+	expected.Thread = []*twitterscraper.Tweet{{ID: "1"}}
+	if diff := cmp.Diff(expected, decoded); diff != "" {
+		t.Error("Decoded tweet does not match the original", diff)
+	}
+}
+
+func TestTweetJSONEmbedded(t *testing.T) {
+	tweet := sampleTweet()
+
+	data, err := twitterscraper.MarshalTweetEmbedded(tweet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded twitterscraper.Tweet
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.InReplyToStatus == nil || decoded.InReplyToStatus.Text != "parent" {
+		t.Fatalf("Expected embedded InReplyToStatus, got %+v", decoded.InReplyToStatus)
+	}
+	if !decoded.InReplyToStatus.TimeParsed.Equal(tweet.InReplyToStatus.TimeParsed) {
+		t.Errorf("Expected embedded TimeParsed %v, got %v", tweet.InReplyToStatus.TimeParsed, decoded.InReplyToStatus.TimeParsed)
+	}
+	if len(decoded.Thread) != 1 || decoded.Thread[0] != decoded.InReplyToStatus {
+		t.Error("Expected thread to reference the embedded tweet")
+	}
+}
+
+func TestProfileJSONRoundTrip(t *testing.T) {
+	joined := time.Date(2010, 1, 18, 8, 49, 30, 0, time.UTC)
+	profile := twitterscraper.Profile{
+		Avatar:         "https://pbs.twimg.com/profile_images/436075027193004032/XlDa2oaz_normal.jpeg",
+		FollowersCount: 5,
+		Joined:         &joined,
+		Name:           "Nomadic",
+		URL:            "https://twitter.com/nomadic_ua",
+		UserID:         "106037940",
+		Username:       "nomadic_ua",
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"schema_version":2`) || !strings.Contains(string(data), `"followers_count":5`) {
+		t.Errorf("Unexpected profile JSON %s", data)
+	}
+	var decoded twitterscraper.Profile
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(profile, decoded); diff != "" {
+		t.Error("Decoded profile does not match the original", diff)
+	}
+}
+
+func TestProfileJSONSchemaVersion1(t *testing.T) {
+	var profile twitterscraper.Profile
+	data := `{"schema_version":1,"username":"nomadic_ua","following_count":330,"friends_count":220,"likes_count":330}`
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		t.Fatal(err)
+	}
+	if profile.FollowingCount != 220 || profile.LikesCount != 330 {
+		t.Errorf("Expected following count restored from friends count, got %+v", profile)
+	}
+
+	var unversioned twitterscraper.Profile
+	data = `{"username":"nomadic_ua","following_count":330,"friends_count":220}`
+	if err := json.Unmarshal([]byte(data), &unversioned); err != nil {
+		t.Fatal(err)
+	}
+	if unversioned.FollowingCount != 330 {
+		t.Errorf("Expected following count kept without a schema version, got %+v", unversioned)
+	}
+}
+
+func TestVideoJSONDuration(t *testing.T) {
+	video := twitterscraper.Video{ID: "1", Duration: 12345 * time.Millisecond}
+	data, err := json.Marshal(video)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"duration_ms":12345`) || strings.Contains(string(data), `"duration"`) {
+		t.Errorf("Unexpected video JSON %s", data)
+	}
+	var decoded twitterscraper.Video
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(video, decoded); diff != "" {
+		t.Error("Decoded video does not match the original", diff)
+	}
+}
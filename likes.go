@@ -0,0 +1,167 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/url"
+)
+
+type favoritersTimeline struct {
+	Data struct {
+		FavoritersTimeline struct {
+			Timeline struct {
+				Instructions []instruction `json:"instructions"`
+			} `json:"timeline"`
+		} `json:"favoriters_timeline"`
+	} `json:"data"`
+}
+
+// GetLikedTweets returns channel with tweets liked by a given user.
+// Requires a session logged in with a user account.
+func (s *Scraper) GetLikedTweets(ctx context.Context, user string, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, user, maxTweetsNbr, s.FetchLikedTweets)
+}
+
+// FetchLikedTweets gets tweets liked by a given user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchLikedTweets(user string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	if err := s.requireUserSession(); err != nil {
+		return nil, "", err
+	}
+	if maxTweetsNbr > 200 {
+		maxTweetsNbr = 200
+	}
+
+	userID, err := s.GetUserIDByScreenName(user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/QK8AVO3RpcnbLPKXLAiVog/Likes")
+	if err != nil {
+		return nil, "", err
+	}
+
+	variables := map[string]interface{}{
+		"userId":                 userID,
+		"count":                  maxTweetsNbr,
+		"includePromotedContent": false,
+		"withClientEventToken":   false,
+		"withBirdwatchNotes":     false,
+		"withVoice":              true,
+		"withV2Timeline":         true,
+	}
+	features := map[string]interface{}{
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var timeline timelineV2
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tweets, nextCursor := timeline.parseTweets()
+	return tweets, nextCursor, nil
+}
+
+// GetLikers returns channel with profiles of users who liked a given tweet.
+// Requires a session logged in with a user account.
+func (s *Scraper) GetLikers(ctx context.Context, tweetID string, maxProfilesNbr int) <-chan *ProfileResult {
+	return getUserTimeline(ctx, tweetID, maxProfilesNbr, s.FetchLikers)
+}
+
+// FetchLikers gets users who liked a given tweet, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchLikers(tweetID string, maxProfilesNbr int, cursor string) ([]*Profile, string, error) {
+	if err := s.requireUserSession(); err != nil {
+		return nil, "", err
+	}
+	if maxProfilesNbr > 100 {
+		maxProfilesNbr = 100
+	}
+
+	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/E-ZTxvWWIkmOKwYdNTEefg/Favoriters")
+	if err != nil {
+		return nil, "", err
+	}
+
+	variables := map[string]interface{}{
+		"tweetId":                tweetID,
+		"count":                  maxProfilesNbr,
+		"includePromotedContent": false,
+	}
+	features := map[string]interface{}{
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var timeline favoritersTimeline
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+
+	profiles, nextCursor := parseTimelineUsers(timeline.Data.FavoritersTimeline.Timeline.Instructions)
+	return profiles, nextCursor, nil
+}
@@ -0,0 +1,75 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLikesRequireUserSession(t *testing.T) {
+	scraper := New()
+	for result := range scraper.GetLikedTweets(context.Background(), "Twitter", 10) {
+		if result.Error != ErrLoginRequired {
+			t.Errorf("Expected ErrLoginRequired, got %v", result.Error)
+		}
+	}
+	scraper.isLogged = true
+	scraper.isOpenAccount = true
+	for result := range scraper.GetLikers(context.Background(), "42", 10) {
+		if result.Error != ErrLoginRequired {
+			t.Errorf("Expected ErrLoginRequired, got %v", result.Error)
+		}
+	}
+}
+
+func TestGetLikedTweets(t *testing.T) {
+	cacheIDs.Store("liker", "id-liker")
+	defer cacheIDs.Delete("liker")
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if userID := requestVariables(t, req)["userId"]; userID != "id-liker" {
+			t.Errorf("Expected userId id-liker, got %v", userID)
+		}
+		detail := tweetDetailResponse(fakeTweet{id: "1", user: "alice", text: "liked"}).(map[string]interface{})
+		instructions := detail["data"].(map[string]interface{})["threaded_conversation_with_injections_v2"]
+		return map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"result": map[string]interface{}{
+			"timeline_v2": map[string]interface{}{"timeline": instructions},
+		}}}}
+	})
+
+	var ids []string
+	for result := range scraper.GetLikedTweets(context.Background(), "liker", 1) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		ids = append(ids, result.ID)
+	}
+	if diff := cmp.Diff([]string{"1"}, ids); diff != "" {
+		t.Error("Liked tweets do not match", diff)
+	}
+}
+
+func TestGetLikers(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		cursor, _ := requestVariables(t, req)["cursor"].(string)
+		instructions := usersTimelineResponse("c1", "alice")
+		if cursor != "" {
+			instructions = usersTimelineResponse("c2")
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"favoriters_timeline": map[string]interface{}{
+			"timeline": map[string]interface{}{"instructions": instructions},
+		}}}
+	})
+
+	var usernames []string
+	for result := range scraper.GetLikers(context.Background(), "42", 10) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		usernames = append(usernames, result.Username)
+	}
+	if diff := cmp.Diff([]string{"alice"}, usernames); diff != "" {
+		t.Error("Likers do not match", diff)
+	}
+}
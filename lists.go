@@ -0,0 +1,296 @@
+package twitterscraper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type listResult struct {
+	IDStr           string `json:"id_str"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Mode            string `json:"mode"`
+	MemberCount     int    `json:"member_count"`
+	SubscriberCount int    `json:"subscriber_count"`
+	CreatedAt       int64  `json:"created_at"`
+	CustomBanner    struct {
+		MediaInfo struct {
+			OriginalImgURL string `json:"original_img_url"`
+		} `json:"media_info"`
+	} `json:"custom_banner_media"`
+	DefaultBanner struct {
+		MediaInfo struct {
+			OriginalImgURL string `json:"original_img_url"`
+		} `json:"media_info"`
+	} `json:"default_banner_media"`
+	UserResults struct {
+		Result userResult `json:"result"`
+	} `json:"user_results"`
+}
+
+func (list *listResult) parse() *List {
+	if list.IDStr == "" {
+		return nil
+	}
+	l := &List{
+		ID:              list.IDStr,
+		Name:            list.Name,
+		Description:     list.Description,
+		IsPrivate:       list.Mode == "Private",
+		MemberCount:     list.MemberCount,
+		SubscriberCount: list.SubscriberCount,
+		Banner:          list.CustomBanner.MediaInfo.OriginalImgURL,
+		OwnerID:         list.UserResults.Result.RestID,
+		OwnerUsername:   list.UserResults.Result.Legacy.ScreenName,
+		URL:             "https://twitter.com/i/lists/" + list.IDStr,
+	}
+	if l.Banner == "" {
+		l.Banner = list.DefaultBanner.MediaInfo.OriginalImgURL
+	}
+	if list.CreatedAt > 0 {
+		created := time.UnixMilli(list.CreatedAt).UTC()
+		l.Created = &created
+	}
+	return l
+}
+
+type timelineInstructions struct {
+	Timeline struct {
+		Instructions []instruction `json:"instructions"`
+	} `json:"timeline"`
+}
+
+type listTimeline struct {
+	Data struct {
+		List struct {
+			listResult
+			TweetsTimeline      timelineInstructions `json:"tweets_timeline"`
+			MembersTimeline     timelineInstructions `json:"members_timeline"`
+			SubscribersTimeline timelineInstructions `json:"subscribers_timeline"`
+		} `json:"list"`
+		User struct {
+			Result struct {
+				Timeline timelineInstructions `json:"timeline"`
+			} `json:"result"`
+		} `json:"user"`
+	} `json:"data"`
+}
+
+// parseLists returns the lists of timeline instructions and the cursor of the
+// next page.
+func parseLists(instructions []instruction) ([]*List, string) {
+	var cursor string
+	var lists []*List
+	for _, instruction := range instructions {
+		for _, entry := range instruction.Entries {
+			if entry.Content.CursorType == "Bottom" {
+				cursor = entry.Content.Value
+				continue
+			}
+			if list := entry.Content.ItemContent.List.parse(); list != nil {
+				lists = append(lists, list)
+			}
+			for _, item := range entry.Content.Items {
+				if list := item.Item.ItemContent.List.parse(); list != nil {
+					lists = append(lists, list)
+				}
+			}
+		}
+	}
+	return lists, cursor
+}
+
+// GetList returns the metadata of a list.
+func (s *Scraper) GetList(listID string) (*List, error) {
+	var timeline listTimeline
+	err := s.requestList("https://twitter.com/i/api/graphql/9hbYpeVBMq8-yB8slayGWQ/ListByRestId", map[string]interface{}{
+		"listId": listID,
+	}, &timeline)
+	if err != nil {
+		return nil, err
+	}
+	list := timeline.Data.List.listResult.parse()
+	if list == nil {
+		return nil, fmt.Errorf("list with ID %s not found", listID)
+	}
+	return list, nil
+}
+
+// GetListTweets returns channel with the latest tweets of a list.
+func (s *Scraper) GetListTweets(ctx context.Context, listID string, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, listID, maxTweetsNbr, s.FetchListTweets)
+}
+
+// FetchListTweets gets the latest tweets of a list, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchListTweets(listID string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	if maxTweetsNbr > 200 {
+		maxTweetsNbr = 200
+	}
+	var timeline listTimeline
+	err := s.requestList("https://twitter.com/i/api/graphql/HjsWc-nwwHKYwHenbHm-tw/ListLatestTweetsTimeline", map[string]interface{}{
+		"listId": listID,
+		"count":  maxTweetsNbr,
+		"cursor": cursor,
+	}, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+	tweets, nextCursor := parseTimelineTweets(timeline.Data.List.TweetsTimeline.Timeline.Instructions)
+	return tweets, nextCursor, nil
+}
+
+// GetListMembers returns channel with profiles of the members of a list.
+func (s *Scraper) GetListMembers(ctx context.Context, listID string, maxProfilesNbr int) <-chan *ProfileResult {
+	return getUserTimeline(ctx, listID, maxProfilesNbr, s.FetchListMembers)
+}
+
+// FetchListMembers gets the members of a list, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchListMembers(listID string, maxProfilesNbr int, cursor string) ([]*Profile, string, error) {
+	if maxProfilesNbr > 200 {
+		maxProfilesNbr = 200
+	}
+	var timeline listTimeline
+	err := s.requestList("https://twitter.com/i/api/graphql/BQp2IEYkgxuSxqbTAr1e1g/ListMembers", map[string]interface{}{
+		"listId": listID,
+		"count":  maxProfilesNbr,
+		"cursor": cursor,
+	}, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+	profiles, nextCursor := parseTimelineUsers(timeline.Data.List.MembersTimeline.Timeline.Instructions)
+	return profiles, nextCursor, nil
+}
+
+// GetListSubscribers returns channel with profiles of the subscribers of a list.
+func (s *Scraper) GetListSubscribers(ctx context.Context, listID string, maxProfilesNbr int) <-chan *ProfileResult {
+	return getUserTimeline(ctx, listID, maxProfilesNbr, s.FetchListSubscribers)
+}
+
+// FetchListSubscribers gets the subscribers of a list, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchListSubscribers(listID string, maxProfilesNbr int, cursor string) ([]*Profile, string, error) {
+	if maxProfilesNbr > 200 {
+		maxProfilesNbr = 200
+	}
+	var timeline listTimeline
+	err := s.requestList("https://twitter.com/i/api/graphql/9TjMJYqGAbbK8T8UhdXXOQ/ListSubscribers", map[string]interface{}{
+		"listId": listID,
+		"count":  maxProfilesNbr,
+		"cursor": cursor,
+	}, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+	profiles, nextCursor := parseTimelineUsers(timeline.Data.List.SubscribersTimeline.Timeline.Instructions)
+	return profiles, nextCursor, nil
+}
+
+// GetUserLists returns channel with the lists owned by a given user, followed
+// by the lists the user is a member of.
+func (s *Scraper) GetUserLists(ctx context.Context, user string) <-chan *ListResult {
+	channel := make(chan *ListResult)
+	go func() {
+		defer close(channel)
+		userID, err := s.GetUserIDByScreenName(user)
+		if err != nil {
+			channel <- &ListResult{Error: err}
+			return
+		}
+
+		seen := make(map[string]bool)
+		for _, endpoint := range []string{
+			"https://twitter.com/i/api/graphql/G6IV7PlDsAwxV8LFi1MFHA/ListOwnerships",
+			"https://twitter.com/i/api/graphql/BlEXXdARdSeL_0KyKHHvvg/ListMemberships",
+		} {
+			var cursor string
+			for {
+				select {
+				case <-ctx.Done():
+					channel <- &ListResult{Error: ctx.Err()}
+					return
+				default:
+				}
+
+				var timeline listTimeline
+				err := s.requestList(endpoint, map[string]interface{}{
+					"userId":                   userID,
+					"count":                    100,
+					"cursor":                   cursor,
+					"isListMembershipShown":    true,
+					"isListMemberTargetUserId": true,
+				}, &timeline)
+				if err != nil {
+					channel <- &ListResult{Error: err}
+					return
+				}
+
+				lists, next := parseLists(timeline.Data.User.Result.Timeline.Timeline.Instructions)
+				for _, list := range lists {
+					if seen[list.ID] {
+						continue
+					}
+					seen[list.ID] = true
+					select {
+					case <-ctx.Done():
+						channel <- &ListResult{Error: ctx.Err()}
+						return
+					case channel <- &ListResult{List: *list}:
+					}
+				}
+				if len(lists) == 0 || next == "" || next == cursor {
+					break
+				}
+				cursor = next
+			}
+		}
+	}()
+	return channel
+}
+
+// requestList calls a list GraphQL operation. An empty cursor variable is
+// left out.
+func (s *Scraper) requestList(endpoint string, variables map[string]interface{}, target interface{}) error {
+	req, err := s.newRequest("GET", endpoint)
+	if err != nil {
+		return err
+	}
+
+	if cursor, ok := variables["cursor"]; ok && cursor == "" {
+		delete(variables, "cursor")
+	}
+	features := map[string]interface{}{
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	return s.RequestAPI(req, target)
+}
@@ -0,0 +1,118 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func listJSON(id, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"id_str":           id,
+		"name":             name,
+		"mode":             "Private",
+		"member_count":     3,
+		"subscriber_count": 1,
+		"created_at":       1352830226000,
+		"default_banner_media": map[string]interface{}{
+			"media_info": map[string]interface{}{"original_img_url": "https://pbs.twimg.com/media/default.png"},
+		},
+		"user_results": map[string]interface{}{"result": userJSON("7", "owner")},
+	}
+}
+
+func TestGetList(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{"list": listJSON("42", "Go")}}
+	})
+	list, err := scraper.GetList("42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2012, 11, 13, 18, 10, 26, 0, time.UTC)
+	expected := &List{
+		ID:              "42",
+		Name:            "Go",
+		IsPrivate:       true,
+		MemberCount:     3,
+		SubscriberCount: 1,
+		Banner:          "https://pbs.twimg.com/media/default.png",
+		Created:         &created,
+		OwnerID:         "7",
+		OwnerUsername:   "owner",
+		URL:             "https://twitter.com/i/lists/42",
+	}
+	if diff := cmp.Diff(expected, list); diff != "" {
+		t.Error("List does not match", diff)
+	}
+}
+
+func TestGetListMembers(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		cursor, _ := requestVariables(t, req)["cursor"].(string)
+		instructions := usersTimelineResponse("c1", "alice", "bob")
+		if cursor != "" {
+			instructions = usersTimelineResponse("c2")
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"list": map[string]interface{}{
+			"members_timeline": map[string]interface{}{"timeline": map[string]interface{}{"instructions": instructions}},
+		}}}
+	})
+	var usernames []string
+	for result := range scraper.GetListMembers(context.Background(), "42", 10) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		usernames = append(usernames, result.Username)
+	}
+	if diff := cmp.Diff([]string{"alice", "bob"}, usernames); diff != "" {
+		t.Error("Members do not match", diff)
+	}
+}
+
+func TestGetUserLists(t *testing.T) {
+	cacheIDs.Store("list_user", "id-list-user")
+	defer cacheIDs.Delete("list_user")
+	listsResponse := func(cursor string, lists ...map[string]interface{}) interface{} {
+		var entries []interface{}
+		for _, list := range lists {
+			entries = append(entries, map[string]interface{}{
+				"entryId": "list-" + list["id_str"].(string),
+				"content": map[string]interface{}{"itemContent": map[string]interface{}{"list": list}},
+			})
+		}
+		entries = append(entries, bottomCursor(cursor))
+		return map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"result": map[string]interface{}{
+			"timeline": map[string]interface{}{"timeline": map[string]interface{}{"instructions": []interface{}{
+				map[string]interface{}{"type": "TimelineAddEntries", "entries": entries},
+			}}},
+		}}}}
+	}
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		cursor, _ := requestVariables(t, req)["cursor"].(string)
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/ListOwnerships") && cursor == "":
+			return listsResponse("o1", listJSON("1", "owned"))
+		case strings.HasSuffix(req.URL.Path, "/ListOwnerships"):
+			return listsResponse("o2")
+		case cursor == "":
+			return listsResponse("m1", listJSON("1", "owned"), listJSON("2", "member"))
+		default:
+			return listsResponse("m2")
+		}
+	})
+	var names []string
+	for result := range scraper.GetUserLists(context.Background(), "list_user") {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		names = append(names, result.Name)
+	}
+	if diff := cmp.Diff([]string{"owned", "member"}, names); diff != "" {
+		t.Error("Lists do not match", diff)
+	}
+}
@@ -0,0 +1,249 @@
+package twitterscraper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+const (
+	// maxTweetsByRestIds is the number of tweets looked up per request.
+	maxTweetsByRestIds = 100
+	// lookupConcurrency bounds the requests LookupTweets runs in parallel.
+	lookupConcurrency = 4
+)
+
+// TweetUnavailableError is the error of a tweet that can't be loaded, because
+// it was deleted, is withheld, or its author is protected or suspended.
+type TweetUnavailableError struct {
+	ID string
+	// Reason given by Twitter, like "Protected", "Suspended" or the text of
+	// the tombstone replacing a deleted tweet. Empty when the tweet is
+	// missing from the response.
+	Reason string
+}
+
+func (e *TweetUnavailableError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("tweet with ID %s not found", e.ID)
+	}
+	return fmt.Sprintf("tweet with ID %s unavailable: %s", e.ID, e.Reason)
+}
+
+type tweetResultsByRestIds struct {
+	Data struct {
+		TweetResult []struct {
+			Result *result `json:"result"`
+		} `json:"tweetResult"`
+	} `json:"data"`
+}
+
+type tweetResultByRestID struct {
+	Data struct {
+		TweetResult struct {
+			Result *result `json:"result"`
+		} `json:"tweetResult"`
+	} `json:"data"`
+}
+
+// LookupTweets loads tweets by ID. Results are in the order of ids; tweets
+// that can't be loaded have Error set to a *TweetUnavailableError. The
+// returned error is set only when a request fails or ctx is done.
+func (s *Scraper) LookupTweets(ctx context.Context, ids []string) ([]TweetResult, error) {
+	results := make([]TweetResult, len(ids))
+	if len(ids) == 0 {
+		return results, nil
+	}
+	// Get the guest token before the batches, so they don't all ask for one.
+	if !s.isLogged {
+		if _, err := s.currentGuestToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	starts := make(chan int)
+	for i := 0; i < lookupConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + maxTweetsByRestIds
+				if end > len(ids) {
+					end = len(ids)
+				}
+				tweets, err := s.fetchTweetsByRestIds(batchCtx, ids[start:end])
+				if err != nil {
+					once.Do(func() { firstErr = err })
+					cancel()
+					continue
+				}
+				for i, id := range ids[start:end] {
+					results[start+i] = parseTweetResult(id, tweets[i])
+				}
+			}
+		}()
+	}
+send:
+	for start := 0; start < len(ids); start += maxTweetsByRestIds {
+		select {
+		case starts <- start:
+		case <-batchCtx.Done():
+			break send
+		}
+	}
+	close(starts)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// parseTweetResult turns the GraphQL result of a tweet into a TweetResult.
+func parseTweetResult(id string, res *result) TweetResult {
+	if res != nil && res.Typename == "TweetWithVisibilityResults" {
+		res = res.Tweet
+	}
+	if res == nil {
+		return TweetResult{Tweet: Tweet{ID: id}, Error: &TweetUnavailableError{ID: id}}
+	}
+	switch res.Typename {
+	case "TweetTombstone":
+		reason := res.Tombstone.Text.Text
+		if reason == "" {
+			reason = "tombstone"
+		}
+		return TweetResult{Tweet: Tweet{ID: id}, Error: &TweetUnavailableError{ID: id, Reason: reason}}
+	case "TweetUnavailable":
+		reason := res.Reason
+		if reason == "" {
+			reason = "unavailable"
+		}
+		return TweetResult{Tweet: Tweet{ID: id}, Error: &TweetUnavailableError{ID: id, Reason: reason}}
+	}
+	tweet := res.parse()
+	if tweet == nil {
+		return TweetResult{Tweet: Tweet{ID: id}, Error: &TweetUnavailableError{ID: id}}
+	}
+	return TweetResult{Tweet: *tweet}
+}
+
+// fetchTweetsByRestIds loads tweets by ID and returns their results in the
+// order of ids. Missing tweets have a nil result.
+func (s *Scraper) fetchTweetsByRestIds(ctx context.Context, ids []string) ([]*result, error) {
+	endpoint := "https://twitter.com/i/api/graphql/BWy5aoI-WvwbeSiHUIf2Hw/TweetResultsByRestIds"
+	variables := map[string]interface{}{
+		"tweetIds":                ids,
+		"withCommunity":           true,
+		"includePromotedContent":  false,
+		"withBirdwatchNotes":      true,
+		"withVoice":               true,
+		"withDownvotePerspective": false,
+	}
+	if len(ids) == 1 {
+		endpoint = "https://twitter.com/i/api/graphql/Xl5pC_lBk_gcO2ItU39DQw/TweetResultByRestId"
+		delete(variables, "tweetIds")
+		variables["tweetId"] = ids[0]
+	}
+
+	req, err := s.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	features := map[string]interface{}{
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	results := make([]*result, len(ids))
+	if len(ids) == 1 {
+		var jsn tweetResultByRestID
+		if err := s.RequestAPI(req, &jsn); err != nil {
+			return nil, err
+		}
+		results[0] = jsn.Data.TweetResult.Result
+		return results, nil
+	}
+
+	var jsn tweetResultsByRestIds
+	if err := s.RequestAPI(req, &jsn); err != nil {
+		return nil, err
+	}
+	// Results are matched by ID, since unavailable tweets may be missing
+	// from the response. Results without an ID fall back to their position
+	// when the response has one result per ID.
+	byID := make(map[string]*result, len(jsn.Data.TweetResult))
+	for _, tweetResult := range jsn.Data.TweetResult {
+		if res := tweetResult.Result; res != nil {
+			if id := resultID(res); id != "" {
+				byID[id] = res
+			}
+		}
+	}
+	if len(jsn.Data.TweetResult) == len(ids) {
+		for i, tweetResult := range jsn.Data.TweetResult {
+			res := tweetResult.Result
+			if res == nil || resultID(res) != "" {
+				continue
+			}
+			if _, ok := byID[ids[i]]; !ok {
+				byID[ids[i]] = res
+			}
+		}
+	}
+	for i, id := range ids {
+		results[i] = byID[id]
+	}
+	return results, nil
+}
+
+// resultID returns the ID of a tweet result, or "" for tombstones and
+// unavailable tweets that don't carry it.
+func resultID(res *result) string {
+	if res.Typename == "TweetWithVisibilityResults" && res.Tweet != nil {
+		res = res.Tweet
+	}
+	if res.RestID != "" {
+		return res.RestID
+	}
+	return res.Legacy.IDStr
+}
@@ -0,0 +1,137 @@
+package twitterscraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLookupTweets(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if !strings.HasSuffix(req.URL.Path, "/TweetResultsByRestIds") {
+			t.Errorf("Unexpected endpoint %s", req.URL.Path)
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"tweetResult": []interface{}{
+			map[string]interface{}{"result": tweetResultJSON("1", "alice")},
+			map[string]interface{}{"result": map[string]interface{}{"__typename": "TweetUnavailable", "reason": "Protected"}},
+			map[string]interface{}{"result": map[string]interface{}{
+				"__typename": "TweetWithVisibilityResults",
+				"tweet":      tweetResultJSON("3", "bob"),
+			}},
+		}}}
+	})
+	results, err := scraper.LookupTweets(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Error != nil || results[0].Username != "alice" {
+		t.Errorf("Unexpected result for 1: %+v", results[0])
+	}
+	if results[2].Error != nil || results[2].Username != "bob" {
+		t.Errorf("Expected TweetWithVisibilityResults unwrapped, got %+v", results[2])
+	}
+	var unavailable *TweetUnavailableError
+	if !errors.As(results[1].Error, &unavailable) || unavailable.Reason != "Protected" {
+		t.Errorf("Expected *TweetUnavailableError for 2, got %v", results[1].Error)
+	}
+}
+
+func TestLookupTweetsSingle(t *testing.T) {
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if !strings.HasSuffix(req.URL.Path, "/TweetResultByRestId") {
+			t.Errorf("Unexpected endpoint %s", req.URL.Path)
+		}
+		if id := requestVariables(t, req)["tweetId"]; id != "5" {
+			t.Errorf("Expected tweetId 5, got %v", id)
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"tweetResult": map[string]interface{}{
+			"result": map[string]interface{}{
+				"__typename": "TweetTombstone",
+				"tombstone":  map[string]interface{}{"text": map[string]interface{}{"text": "This Post was deleted by the Post author."}},
+			},
+		}}}
+	})
+	results, err := scraper.LookupTweets(context.Background(), []string{"5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unavailable *TweetUnavailableError
+	if !errors.As(results[0].Error, &unavailable) || !strings.Contains(unavailable.Reason, "deleted") {
+		t.Errorf("Expected tombstone reason, got %v", results[0].Error)
+	}
+}
+
+// TestLookupTweetsBatches runs a guest session through concurrent batches
+// whose responses reset the guest token; run it with -race.
+func TestLookupTweetsBatches(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	var batches [][]interface{}
+	scraper := New()
+	scraper.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		if strings.HasSuffix(req.URL.Path, "/guest/activate.json") {
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(`{"guest_token": "1"}`)), Request: req}, nil
+		}
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		ids := requestVariables(t, req)["tweetIds"].([]interface{})
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+		var tweets []interface{}
+		for _, id := range ids {
+			tweets = append(tweets, map[string]interface{}{"result": tweetResultJSON(id.(string), "alice")})
+		}
+		body, _ := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"tweetResult": tweets}})
+		header.Set("X-Rate-Limit-Remaining", "0")
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewReader(body)), Request: req}, nil
+	})
+
+	ids := make([]string, 950)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+	results, err := scraper.LookupTweets(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 10 {
+		t.Errorf("Expected 10 batches, got %d", len(batches))
+	}
+	for _, batch := range batches {
+		if len(batch) != 100 && len(batch) != 50 {
+			t.Errorf("Unexpected batch of %d IDs", len(batch))
+		}
+	}
+	if maxInFlight < 2 || maxInFlight > lookupConcurrency {
+		t.Errorf("Expected up to %d batches in flight, got %d", lookupConcurrency, maxInFlight)
+	}
+	for i, result := range results {
+		if result.Error != nil || result.ID != ids[i] {
+			t.Fatalf("Unexpected result %d: %+v", i, result)
+		}
+	}
+}
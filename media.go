@@ -0,0 +1,166 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypeMP4 = "video/mp4"
+	contentTypeHLS = "application/x-mpegURL"
+)
+
+// reVariantSize matches the resolution in the URL of a video variant, like
+// ".../vid/avc1/1280x720/....mp4".
+var reVariantSize = regexp.MustCompile(`/(\d+)x(\d+)/`)
+
+// parseMedia adds the photos, videos and GIFs of the extended entities of a
+// tweet.
+func parseMedia(tw *Tweet, media []legacyMedia) {
+	for i := range media {
+		m := &media[i]
+		switch m.Type {
+		case "photo":
+			tw.Photos = append(tw.Photos, Photo{
+				ID:           m.IDStr,
+				URL:          m.MediaURLHttps,
+				AltText:      m.ExtAltText,
+				Width:        m.OriginalInfo.Width,
+				Height:       m.OriginalInfo.Height,
+				FocusRects:   m.OriginalInfo.FocusRects,
+				Availability: m.ExtMediaAvailability.Status,
+			})
+		case "video":
+			video := Video{
+				ID:           m.IDStr,
+				Preview:      m.MediaURLHttps,
+				AltText:      m.ExtAltText,
+				Width:        m.OriginalInfo.Width,
+				Height:       m.OriginalInfo.Height,
+				AspectRatio:  m.VideoInfo.AspectRatio,
+				Duration:     time.Duration(m.VideoInfo.DurationMillis) * time.Millisecond,
+				Views:        m.viewCount(),
+				Availability: m.ExtMediaAvailability.Status,
+				Variants:     m.variants(),
+			}
+			maxBitrate := 0
+			for _, variant := range video.Variants {
+				if variant.ContentType == contentTypeHLS {
+					video.HLSURL = variant.URL
+				}
+				if variant.Bitrate > maxBitrate {
+					video.URL = strings.TrimSuffix(variant.URL, "?tag=10")
+					maxBitrate = variant.Bitrate
+				}
+			}
+			tw.Videos = append(tw.Videos, video)
+		case "animated_gif":
+			gif := GIF{
+				ID:           m.IDStr,
+				Preview:      m.MediaURLHttps,
+				AltText:      m.ExtAltText,
+				Width:        m.OriginalInfo.Width,
+				Height:       m.OriginalInfo.Height,
+				AspectRatio:  m.VideoInfo.AspectRatio,
+				Availability: m.ExtMediaAvailability.Status,
+				Variants:     m.variants(),
+			}
+			// Twitter's API doesn't provide bitrate for GIFs, (it's always set to zero).
+			// Therefore we check for `>=` instead of `>` in the loop below.
+			// Also, GIFs have just a single variant today. Just in case that changes in the future,
+			// and there will be multiple variants, we'll pick the one with the highest bitrate,
+			// if other one will have a non-zero bitrate.
+			maxBitrate := 0
+			for _, variant := range gif.Variants {
+				if variant.Bitrate >= maxBitrate {
+					gif.URL = variant.URL
+					maxBitrate = variant.Bitrate
+				}
+			}
+			tw.GIFs = append(tw.GIFs, gif)
+		}
+
+		if !tw.SensitiveContent {
+			sensitive := m.ExtSensitiveMediaWarning
+			tw.SensitiveContent = sensitive.AdultContent || sensitive.GraphicViolence || sensitive.Other
+		}
+	}
+}
+
+func (m *legacyMedia) variants() []Variant {
+	var variants []Variant
+	for _, v := range m.VideoInfo.Variants {
+		variant := Variant{
+			Bitrate:     v.Bitrate,
+			ContentType: v.ContentType,
+			URL:         v.URL,
+		}
+		if match := reVariantSize.FindStringSubmatch(v.URL); match != nil {
+			variant.Width, _ = strconv.Atoi(match[1])
+			variant.Height, _ = strconv.Atoi(match[2])
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+func (m *legacyMedia) viewCount() int {
+	if views := parseCount(m.MediaStats.ViewCount); views > 0 {
+		return views
+	}
+	var r struct {
+		Ok struct {
+			ViewCount json.RawMessage `json:"viewCount"`
+		} `json:"ok"`
+	}
+	if json.Unmarshal(m.Ext.MediaStats.R, &r) != nil {
+		return 0
+	}
+	return parseCount(r.Ok.ViewCount)
+}
+
+// parseCount decodes a count given either as a number or a string.
+func parseCount(raw json.RawMessage) int {
+	count, _ := strconv.Atoi(strings.Trim(string(raw), `"`))
+	return count
+}
+
+// VariantByResolution returns the MP4 variant with the highest resolution
+// that fits in maxWidth x maxHeight. A zero limit is unbounded. It returns
+// nil when no variant fits.
+func (video *Video) VariantByResolution(maxWidth, maxHeight int) *Variant {
+	var best *Variant
+	for i := range video.Variants {
+		v := &video.Variants[i]
+		if v.ContentType != contentTypeMP4 ||
+			(maxWidth > 0 && v.Width > maxWidth) ||
+			(maxHeight > 0 && v.Height > maxHeight) {
+			continue
+		}
+		if best == nil || v.Width*v.Height > best.Width*best.Height ||
+			(v.Width*v.Height == best.Width*best.Height && v.Bitrate > best.Bitrate) {
+			best = v
+		}
+	}
+	return best
+}
+
+// VariantByBitrate returns the MP4 variant with the highest bitrate not
+// above maxBitrate, in bits per second. A zero limit is unbounded. It
+// returns nil when no variant fits.
+func (video *Video) VariantByBitrate(maxBitrate int) *Variant {
+	var best *Variant
+	for i := range video.Variants {
+		v := &video.Variants[i]
+		if v.ContentType != contentTypeMP4 || (maxBitrate > 0 && v.Bitrate > maxBitrate) {
+			continue
+		}
+		if best == nil || v.Bitrate > best.Bitrate {
+			best = v
+		}
+	}
+	return best
+}
@@ -0,0 +1,176 @@
+package media
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reBandwidth = regexp.MustCompile(`BANDWIDTH=(\d+)`)
+	reURI       = regexp.MustCompile(`URI="([^"]*)"`)
+)
+
+// fetchHLS downloads the segments of an HLS playlist and concatenates them
+// into partial. For a master playlist, the stream with the highest bandwidth
+// is downloaded. Audio renditions in separate playlists are not merged.
+//
+// Progress is stored next to partial, so an interrupted download resumes
+// after the last complete segment. It returns ".mp4" for fragmented MP4
+// streams and ".ts" for MPEG-TS ones.
+func (d *Downloader) fetchHLS(ctx context.Context, playlistURL string, partial string) (string, error) {
+	base, lines, err := d.getPlaylist(ctx, playlistURL)
+	if err != nil {
+		return "", err
+	}
+	if stream := bestStream(base, lines); stream != "" {
+		base, lines, err = d.getPlaylist(ctx, stream)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	ext := ".ts"
+	var segments []string
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			if match := reURI.FindStringSubmatch(line); match != nil {
+				segments = append(segments, resolve(base, match[1]))
+				ext = ".mp4"
+			}
+		case line != "" && !strings.HasPrefix(line, "#"):
+			segments = append(segments, resolve(base, line))
+		}
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("media: %s: no segments in playlist", playlistURL)
+	}
+
+	progress := partial + ".progress"
+	done, size := readProgress(progress)
+	f, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := f.Truncate(size); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	for i := done; i < len(segments); i++ {
+		n, err := d.copySegment(ctx, f, segments[i])
+		if err != nil {
+			return "", err
+		}
+		size += n
+		if err := os.WriteFile(progress, []byte(fmt.Sprintf("%d %d", i+1, size)), 0o644); err != nil {
+			return "", err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return ext, os.Remove(progress)
+}
+
+func (d *Downloader) copySegment(ctx context.Context, w io.Writer, segmentURL string) (int64, error) {
+	resp, err := d.get(ctx, segmentURL)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
+
+// getPlaylist loads a playlist and returns its URL, for resolving relative
+// URIs, and its lines.
+func (d *Downloader) getPlaylist(ctx context.Context, playlistURL string) (*url.URL, []string, error) {
+	base, err := url.Parse(playlistURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := d.get(ctx, playlistURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return base, lines, nil
+}
+
+func (d *Downloader) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("media: %s: response status %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+// bestStream returns the URL of the stream with the highest bandwidth of a
+// master playlist, or "" for a media playlist.
+func bestStream(base *url.URL, lines []string) string {
+	best, bestBandwidth := "", -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "#EXT-X-STREAM-INF:") || i+1 >= len(lines) {
+			continue
+		}
+		bandwidth := 0
+		if match := reBandwidth.FindStringSubmatch(line); match != nil {
+			bandwidth, _ = strconv.Atoi(match[1])
+		}
+		if bandwidth > bestBandwidth {
+			best, bestBandwidth = resolve(base, lines[i+1]), bandwidth
+		}
+	}
+	return best
+}
+
+func resolve(base *url.URL, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// readProgress returns the number of segments written and their total size,
+// or zeros when there is no progress to resume.
+func readProgress(name string) (int, int64) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return 0, 0
+	}
+	var done int
+	var size int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &done, &size); err != nil {
+		return 0, 0
+	}
+	return done, size
+}
@@ -0,0 +1,538 @@
+// Package media downloads the photos, videos and GIFs of scraped tweets.
+//
+// Files are stored content-addressed under the download directory, as
+// <sha256[:2]>/<sha256><ext>, so media shared by several tweets is stored
+// once. Interrupted downloads are resumed with HTTP range requests, and HLS
+// videos segment by segment. A manifest, manifest.json in the download
+// directory, maps tweet IDs to their files and is reloaded by New, so media
+// downloaded before is not fetched again.
+//
+// Use the HTTP client of the scraper to download through its proxy. It has
+// no overall timeout, unlike the client the scraper sends API requests with:
+//
+//	downloader, err := media.New(scraper.HTTPClient(), media.Config{Dir: "media"})
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+)
+
+// DefaultConcurrency is the number of files downloaded in parallel when
+// Config.Concurrency is not set.
+const DefaultConcurrency = 4
+
+const (
+	manifestName = "manifest.json"
+	partialDir   = ".partial"
+)
+
+// Kind of media.
+type Kind string
+
+// Kinds of media.
+const (
+	KindPhoto Kind = "photo"
+	KindVideo Kind = "video"
+	KindGIF   Kind = "gif"
+)
+
+// Config of a Downloader.
+type Config struct {
+	// Dir is the directory files and the manifest are stored in. Required.
+	Dir string
+	// Concurrency is the number of files downloaded in parallel.
+	Concurrency int
+	// SelectVideo chooses the variant of a video to download, for instance
+	// with Video.VariantByResolution. A nil func, or a nil result, selects
+	// the MP4 with the highest bitrate, or the HLS playlist when the video
+	// has no MP4.
+	SelectVideo func(video *twitterscraper.Video) *twitterscraper.Variant
+}
+
+// File is a downloaded media file.
+type File struct {
+	TweetID string `json:"tweet_id"`
+	MediaID string `json:"media_id"`
+	Kind    Kind   `json:"kind"`
+	// URL the file was downloaded from.
+	URL string `json:"url"`
+	// Path of the file, relative to the download directory, with forward
+	// slashes.
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// item is a media file to download.
+type item struct {
+	tweetID string
+	mediaID string
+	kind    Kind
+	url     string
+	hls     bool
+}
+
+// Downloader of tweet media. It is safe for concurrent use.
+type Downloader struct {
+	client *http.Client
+	config Config
+	sem    chan struct{}
+
+	mu       sync.Mutex
+	tweets   map[string][]File
+	byURL    map[string]File
+	inflight map[string]chan struct{}
+}
+
+// New creates a Downloader fetching files with client, and loads the
+// manifest of the download directory when there is one. A nil client uses
+// http.DefaultClient.
+func New(client *http.Client, config Config) (*Downloader, error) {
+	if config.Dir == "" {
+		return nil, errors.New("media: Dir is required")
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if err := os.MkdirAll(filepath.Join(config.Dir, partialDir), 0o755); err != nil {
+		return nil, err
+	}
+	d := &Downloader{
+		client:   client,
+		config:   config,
+		sem:      make(chan struct{}, config.Concurrency),
+		tweets:   make(map[string][]File),
+		byURL:    make(map[string]File),
+		inflight: make(map[string]chan struct{}),
+	}
+	if err := d.loadManifest(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DownloadTweet downloads the media of a tweet and saves the manifest. On
+// error, the files downloaded so far are kept in the manifest.
+func (d *Downloader) DownloadTweet(ctx context.Context, tweet *twitterscraper.Tweet) ([]File, error) {
+	files, err := d.downloadTweet(ctx, tweet)
+	if saveErr := d.SaveManifest(); err == nil {
+		err = saveErr
+	}
+	return files, err
+}
+
+// DownloadTweets downloads the media of every tweet received from tweets,
+// saves the manifest and returns the number of files downloaded. It stops
+// at the first result error or download error; cancel the scraping context
+// to stop the producer in that case.
+func (d *Downloader) DownloadTweets(ctx context.Context, tweets <-chan *twitterscraper.TweetResult) (int, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		n        int
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	slots := make(chan struct{}, d.config.Concurrency)
+loop:
+	for !failed() {
+		select {
+		case <-ctx.Done():
+			fail(ctx.Err())
+			break loop
+		case result, ok := <-tweets:
+			if !ok {
+				break loop
+			}
+			if result.Error != nil {
+				fail(result.Error)
+				break loop
+			}
+			slots <- struct{}{}
+			wg.Add(1)
+			go func(tweet twitterscraper.Tweet) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				files, err := d.downloadTweet(ctx, &tweet)
+				mu.Lock()
+				n += len(files)
+				mu.Unlock()
+				if err != nil {
+					fail(err)
+				}
+			}(result.Tweet)
+		}
+	}
+	wg.Wait()
+
+	if err := d.SaveManifest(); err != nil {
+		fail(err)
+	}
+	return n, firstErr
+}
+
+// Manifest returns the downloaded files by tweet ID.
+func (d *Downloader) Manifest() map[string][]File {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	manifest := make(map[string][]File, len(d.tweets))
+	for id, files := range d.tweets {
+		manifest[id] = append([]File(nil), files...)
+	}
+	return manifest
+}
+
+// SaveManifest writes the manifest to the download directory.
+func (d *Downloader) SaveManifest() error {
+	d.mu.Lock()
+	data, err := json.MarshalIndent(manifest{Tweets: d.tweets}, "", "  ")
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(d.config.Dir, manifestName)
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+type manifest struct {
+	Tweets map[string][]File `json:"tweets"`
+}
+
+func (d *Downloader) loadManifest() error {
+	data, err := os.ReadFile(filepath.Join(d.config.Dir, manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("media: %s: %w", manifestName, err)
+	}
+	for id, files := range m.Tweets {
+		d.tweets[id] = files
+		for _, file := range files {
+			d.byURL[file.URL] = file
+		}
+	}
+	return nil
+}
+
+func (d *Downloader) downloadTweet(ctx context.Context, tweet *twitterscraper.Tweet) ([]File, error) {
+	items := tweetItems(tweet, d.config.SelectVideo)
+	results := make([]*File, len(items))
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			file, err := d.download(ctx, items[i])
+			if err != nil {
+				once.Do(func() { firstErr = err })
+				return
+			}
+			results[i] = file
+		}(i)
+	}
+	wg.Wait()
+
+	var files []File
+	for _, file := range results {
+		if file != nil {
+			files = append(files, *file)
+		}
+	}
+	d.record(tweet.ID, files)
+	return files, firstErr
+}
+
+// record adds files to the manifest entry of a tweet.
+func (d *Downloader) record(tweetID string, files []File) {
+	if len(files) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	existing := d.tweets[tweetID]
+	for _, file := range files {
+		replaced := false
+		for i := range existing {
+			if existing[i].MediaID == file.MediaID && existing[i].Kind == file.Kind {
+				existing[i] = file
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, file)
+		}
+	}
+	d.tweets[tweetID] = existing
+}
+
+// download fetches an item, unless the same URL was downloaded before.
+func (d *Downloader) download(ctx context.Context, it item) (*File, error) {
+	release, err := d.lock(ctx, it.url)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	d.mu.Lock()
+	file, ok := d.byURL[it.url]
+	d.mu.Unlock()
+	if ok {
+		if _, err := os.Stat(d.abs(file.Path)); err == nil {
+			file.TweetID, file.MediaID, file.Kind = it.tweetID, it.mediaID, it.kind
+			return &file, nil
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case d.sem <- struct{}{}:
+	}
+	defer func() { <-d.sem }()
+
+	partial := filepath.Join(d.config.Dir, partialDir, hashString(it.url))
+	var ext string
+	if it.hls {
+		ext, err = d.fetchHLS(ctx, it.url, partial)
+	} else {
+		ext, err = d.fetch(ctx, it.url, partial)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sum, size, err := hashFile(partial)
+	if err != nil {
+		return nil, err
+	}
+	rel := path.Join(sum[:2], sum+ext)
+	if err := os.MkdirAll(filepath.Dir(d.abs(rel)), 0o755); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(d.abs(rel)); err == nil {
+		err = os.Remove(partial)
+	} else {
+		err = os.Rename(partial, d.abs(rel))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file = File{
+		TweetID: it.tweetID,
+		MediaID: it.mediaID,
+		Kind:    it.kind,
+		URL:     it.url,
+		Path:    rel,
+		SHA256:  sum,
+		Size:    size,
+	}
+	d.mu.Lock()
+	d.byURL[it.url] = file
+	d.mu.Unlock()
+	return &file, nil
+}
+
+// fetch downloads a URL to partial, resuming from its current size, and
+// returns the file extension of the URL.
+func (d *Downloader) fetch(ctx context.Context, rawURL string, partial string) (string, error) {
+	f, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// The server ignored the range; start over.
+		if err := f.Truncate(0); err != nil {
+			return "", err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset == 0 {
+			return "", fmt.Errorf("media: %s: response status %s", rawURL, resp.Status)
+		}
+		// The partial file is complete already.
+		return urlExt(rawURL), nil
+	default:
+		return "", fmt.Errorf("media: %s: response status %s", rawURL, resp.Status)
+	}
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return "", err
+	}
+	return urlExt(rawURL), f.Close()
+}
+
+// lock serializes the downloads of a URL.
+func (d *Downloader) lock(ctx context.Context, key string) (func(), error) {
+	for {
+		d.mu.Lock()
+		busy, ok := d.inflight[key]
+		if !ok {
+			done := make(chan struct{})
+			d.inflight[key] = done
+			d.mu.Unlock()
+			return func() {
+				d.mu.Lock()
+				delete(d.inflight, key)
+				d.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		d.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-busy:
+		}
+	}
+}
+
+func (d *Downloader) abs(rel string) string {
+	return filepath.Join(d.config.Dir, filepath.FromSlash(rel))
+}
+
+// tweetItems lists the media files of a tweet.
+func tweetItems(tweet *twitterscraper.Tweet, selectVideo func(*twitterscraper.Video) *twitterscraper.Variant) []item {
+	var items []item
+	for _, photo := range tweet.Photos {
+		items = append(items, item{tweetID: tweet.ID, mediaID: photo.ID, kind: KindPhoto, url: originalPhotoURL(photo.URL)})
+	}
+	for i := range tweet.Videos {
+		video := &tweet.Videos[i]
+		var variant *twitterscraper.Variant
+		if selectVideo != nil {
+			variant = selectVideo(video)
+		}
+		if variant == nil {
+			variant = video.VariantByBitrate(0)
+		}
+		switch {
+		case variant != nil:
+			items = append(items, item{tweetID: tweet.ID, mediaID: video.ID, kind: KindVideo, url: variant.URL, hls: isHLS(variant)})
+		case video.URL != "":
+			items = append(items, item{tweetID: tweet.ID, mediaID: video.ID, kind: KindVideo, url: video.URL})
+		case video.HLSURL != "":
+			items = append(items, item{tweetID: tweet.ID, mediaID: video.ID, kind: KindVideo, url: video.HLSURL, hls: true})
+		}
+	}
+	for _, gif := range tweet.GIFs {
+		if gif.URL != "" {
+			items = append(items, item{tweetID: tweet.ID, mediaID: gif.ID, kind: KindGIF, url: gif.URL})
+		}
+	}
+	return items
+}
+
+// originalPhotoURL returns the URL of the original size of a photo.
+func originalPhotoURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set("name", "orig")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func isHLS(variant *twitterscraper.Variant) bool {
+	return variant.ContentType == "application/x-mpegURL" || path.Ext(urlPath(variant.URL)) == ".m3u8"
+}
+
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
+
+func urlExt(rawURL string) string {
+	if ext := path.Ext(urlPath(rawURL)); ext != "" {
+		return ext
+	}
+	return ".bin"
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashFile(name string) (string, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
@@ -0,0 +1,236 @@
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/masa-finance/masa-twitter-scraper/media"
+)
+
+var (
+	photoData = []byte("original photo bytes")
+	videoData = bytes.Repeat([]byte("0123456789"), 1000)
+)
+
+type server struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	// abortVideo makes the next video response stop halfway.
+	abortVideo bool
+}
+
+func newServer(t *testing.T) *server {
+	s := &server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		abort := s.abortVideo && r.URL.Path == "/vid/1280x720/video.mp4"
+		s.abortVideo = s.abortVideo && !abort
+		s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/media/photo.jpg":
+			if r.URL.Query().Get("name") != "orig" {
+				http.Error(w, "expected name=orig", http.StatusBadRequest)
+				return
+			}
+			w.Write(photoData)
+		case "/vid/1280x720/video.mp4", "/vid/480x270/low.mp4":
+			if abort {
+				w.Header().Set("Content-Length", fmt.Sprint(len(videoData)))
+				w.Write(videoData[:len(videoData)/2])
+				w.(http.Flusher).Flush()
+				panic(http.ErrAbortHandler)
+			}
+			http.ServeContent(w, r, "video.mp4", time.Time{}, bytes.NewReader(videoData))
+		case "/pl/master.m3u8":
+			fmt.Fprint(w, "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=256000\nlow/index.m3u8\n#EXT-X-STREAM-INF:BANDWIDTH=2176000\nhigh/index.m3u8\n")
+		case "/pl/high/index.m3u8":
+			fmt.Fprint(w, "#EXTM3U\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:3.0,\nseg1.m4s\n#EXTINF:3.0,\n/pl/high/seg2.m4s\n#EXT-X-ENDLIST\n")
+		case "/pl/high/init.mp4", "/pl/high/seg1.m4s", "/pl/high/seg2.m4s":
+			fmt.Fprint(w, filepath.Base(r.URL.Path)+";")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *server) tweet() *twitterscraper.Tweet {
+	return &twitterscraper.Tweet{
+		ID:     "1",
+		Photos: []twitterscraper.Photo{{ID: "10", URL: s.URL + "/media/photo.jpg"}},
+		Videos: []twitterscraper.Video{{
+			ID: "11",
+			Variants: []twitterscraper.Variant{
+				{ContentType: "application/x-mpegURL", URL: s.URL + "/pl/master.m3u8"},
+				{Bitrate: 256000, ContentType: "video/mp4", URL: s.URL + "/vid/480x270/low.mp4", Width: 480, Height: 270},
+				{Bitrate: 2176000, ContentType: "video/mp4", URL: s.URL + "/vid/1280x720/video.mp4", Width: 1280, Height: 720},
+			},
+		}},
+	}
+}
+
+func sha(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func readFile(t *testing.T, dir string, file media.File) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDownloadTweet(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	downloader, err := media.New(srv.Client(), media.Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := downloader.DownloadTweet(context.Background(), srv.tweet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %+v", files)
+	}
+	photo, video := files[0], files[1]
+	if photo.Kind != media.KindPhoto || !bytes.Equal(readFile(t, dir, photo), photoData) {
+		t.Errorf("Unexpected photo %+v", photo)
+	}
+	if photo.Path != sha(photoData)[:2]+"/"+sha(photoData)+".jpg" || photo.SHA256 != sha(photoData) {
+		t.Errorf("Expected content-addressed path, got %s", photo.Path)
+	}
+	if video.Kind != media.KindVideo || !strings.HasSuffix(video.URL, "/vid/1280x720/video.mp4") {
+		t.Errorf("Expected highest bitrate variant, got %+v", video)
+	}
+	if !bytes.Equal(readFile(t, dir, video), videoData) || video.Size != int64(len(videoData)) {
+		t.Errorf("Unexpected video content for %+v", video)
+	}
+
+	// The manifest is reloaded, and nothing is downloaded again.
+	reloaded, err := media.New(srv.Client(), media.Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Manifest()["1"]; len(got) != 2 || got[1].SHA256 != video.SHA256 {
+		t.Errorf("Unexpected manifest %+v", got)
+	}
+	requests := len(srv.requests)
+	if _, err := reloaded.DownloadTweet(context.Background(), srv.tweet()); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.requests) != requests {
+		t.Errorf("Expected no new request, got %d", len(srv.requests)-requests)
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	srv := newServer(t)
+	srv.abortVideo = true
+	dir := t.TempDir()
+	downloader, err := media.New(srv.Client(), media.Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweet := srv.tweet()
+	tweet.Photos = nil
+	if _, err := downloader.DownloadTweet(context.Background(), tweet); err == nil {
+		t.Fatal("Expected the interrupted download to fail")
+	}
+	files, err := downloader.DownloadTweet(context.Background(), tweet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := srv.requests[len(srv.requests)-1]
+	if want := fmt.Sprintf("bytes=%d-", len(videoData)/2); last.Header.Get("Range") != want {
+		t.Errorf("Expected Range %q, got %q", want, last.Header.Get("Range"))
+	}
+	if !bytes.Equal(readFile(t, dir, files[0]), videoData) {
+		t.Error("Expected resumed video to match")
+	}
+}
+
+func TestDownloadHLS(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	downloader, err := media.New(srv.Client(), media.Config{
+		Dir: dir,
+		SelectVideo: func(video *twitterscraper.Video) *twitterscraper.Variant {
+			return &video.Variants[0]
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := downloader.DownloadTweet(context.Background(), &twitterscraper.Tweet{ID: "2", Videos: srv.tweet().Videos})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || !strings.HasSuffix(files[0].Path, ".mp4") {
+		t.Fatalf("Unexpected files %+v", files)
+	}
+	if got := string(readFile(t, dir, files[0])); got != "init.mp4;seg1.m4s;seg2.m4s;" {
+		t.Errorf("Unexpected assembled stream %q", got)
+	}
+}
+
+func TestDownloadTweets(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	downloader, err := media.New(srv.Client(), media.Config{Dir: dir, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweets := make(chan *twitterscraper.TweetResult, 2)
+	first, second := srv.tweet(), srv.tweet()
+	second.ID = "2"
+	tweets <- &twitterscraper.TweetResult{Tweet: *first}
+	tweets <- &twitterscraper.TweetResult{Tweet: *second}
+	close(tweets)
+	n, err := downloader.DownloadTweets(context.Background(), tweets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("Expected 4 files, got %d", n)
+	}
+	manifest := downloader.Manifest()
+	if len(manifest["1"]) != 2 || len(manifest["2"]) != 2 || manifest["2"][0].Path != manifest["1"][0].Path {
+		t.Errorf("Expected shared media stored once, got %+v", manifest)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScraperHTTPClient(t *testing.T) {
+	transport := &http.Transport{}
+	scraper := twitterscraper.New().WithTransport(transport)
+	client := scraper.HTTPClient()
+	if client.Timeout != 0 {
+		t.Errorf("Expected no overall timeout, got %s", client.Timeout)
+	}
+	if client.Transport != transport || client.Jar == nil {
+		t.Error("Expected the client to share the transport and cookies of the scraper")
+	}
+}
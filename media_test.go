@@ -0,0 +1,100 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const videoMediaJSON = `{
+	"id_str": "2", "type": "video", "media_url_https": "https://pbs.twimg.com/thumb.jpg",
+	"ext_alt_text": "a video",
+	"original_info": {"width": 1920, "height": 1080},
+	"ext_media_availability": {"status": "Available"},
+	"video_info": {
+		"aspect_ratio": [16, 9],
+		"duration_millis": 12500,
+		"variants": [
+			{"content_type": "application/x-mpegURL", "url": "https://video.twimg.com/pl/playlist.m3u8?tag=10"},
+			{"bitrate": 256000, "content_type": "video/mp4", "url": "https://video.twimg.com/vid/480x270/low.mp4?tag=10"},
+			{"bitrate": 2176000, "content_type": "video/mp4", "url": "https://video.twimg.com/vid/1280x720/high.mp4?tag=10"},
+			{"bitrate": 832000, "content_type": "video/mp4", "url": "https://video.twimg.com/vid/640x360/mid.mp4?tag=10"}
+		]
+	}
+}`
+
+func TestParseMediaGraphQL(t *testing.T) {
+	var res result
+	err := json.Unmarshal([]byte(`{
+		"__typename": "Tweet",
+		"legacy": {"id_str": "1", "extended_entities": {"media": [
+			{
+				"id_str": "3", "type": "photo", "media_url_https": "https://pbs.twimg.com/media/a.jpg",
+				"ext_alt_text": "a photo",
+				"original_info": {"width": 800, "height": 600, "focus_rects": [{"x": 0, "y": 0, "w": 800, "h": 448}]}
+			},
+			`+videoMediaJSON[:len(videoMediaJSON)-1]+`, "mediaStats": {"viewCount": 1234}}
+		]}}
+	}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweet := res.parse()
+	if len(tweet.Photos) != 1 || len(tweet.Videos) != 1 {
+		t.Fatalf("Expected a photo and a video, got %+v %+v", tweet.Photos, tweet.Videos)
+	}
+	photo := tweet.Photos[0]
+	if photo.AltText != "a photo" || photo.Width != 800 || photo.Height != 600 || len(photo.FocusRects) != 1 || photo.FocusRects[0].H != 448 {
+		t.Errorf("Unexpected photo %+v", photo)
+	}
+	video := tweet.Videos[0]
+	if video.URL != "https://video.twimg.com/vid/1280x720/high.mp4" {
+		t.Errorf("Expected highest bitrate URL, got %s", video.URL)
+	}
+	if video.AltText != "a video" || video.Duration != 12500*time.Millisecond || video.Views != 1234 ||
+		len(video.AspectRatio) != 2 || video.Availability != "Available" {
+		t.Errorf("Unexpected video %+v", video)
+	}
+	if video.HLSURL != "https://video.twimg.com/pl/playlist.m3u8?tag=10" || len(video.Variants) != 4 {
+		t.Errorf("Unexpected variants %s %+v", video.HLSURL, video.Variants)
+	}
+}
+
+func TestParseMediaLegacyViewCount(t *testing.T) {
+	for raw, want := range map[string]int{
+		`{"r": {"ok": {"viewCount": "5678"}}, "ttl": -1}`: 5678,
+		`{"r": "Missing", "ttl": -1}`:                     0,
+	} {
+		var timeline timelineV1
+		err := json.Unmarshal([]byte(`{"globalObjects": {"tweets": {"1": {"id_str": "1", "extended_entities": {"media": [
+			`+videoMediaJSON[:len(videoMediaJSON)-1]+`, "ext": {"mediaStats": `+raw+`}}
+		]}}}}}`), &timeline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tweet := timeline.parseTweet("1")
+		if len(tweet.Videos) != 1 || tweet.Videos[0].Views != want {
+			t.Errorf("Expected %d views for %s, got %+v", want, raw, tweet.Videos)
+		}
+	}
+}
+
+func TestVideoVariantSelection(t *testing.T) {
+	var m legacyMedia
+	if err := json.Unmarshal([]byte(videoMediaJSON), &m); err != nil {
+		t.Fatal(err)
+	}
+	video := Video{Variants: m.variants()}
+	if v := video.VariantByResolution(0, 0); v == nil || v.Height != 720 {
+		t.Errorf("Expected 720p variant, got %+v", v)
+	}
+	if v := video.VariantByResolution(0, 480); v == nil || v.Height != 360 {
+		t.Errorf("Expected 360p variant, got %+v", v)
+	}
+	if v := video.VariantByBitrate(1000000); v == nil || v.Bitrate != 832000 {
+		t.Errorf("Expected 832k variant, got %+v", v)
+	}
+	if v := video.VariantByBitrate(100000); v != nil {
+		t.Errorf("Expected no variant, got %+v", v)
+	}
+}
@@ -0,0 +1,101 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const metadataLegacyJSON = `{
+	"id_str": "1", "user_id_str": "10", "full_text": "@bob hello",
+	"lang": "en",
+	"source": "<a href=\"https://mobile.twitter.com\" rel=\"nofollow\">Twitter Web App</a>",
+	"quote_count": 3,
+	"bookmark_count": 4,
+	"possibly_sensitive": true,
+	"is_quote_status": true,
+	"conversation_control": {"policy": "Community"},
+	"withheld_in_countries": ["DE", "FR"],
+	"display_text_range": [5, 10],
+	"favorited": true,
+	"retweeted": false
+}`
+
+func TestParseTweetMetadata(t *testing.T) {
+	var timeline timelineV1
+	err := json.Unmarshal([]byte(`{"globalObjects": {"tweets": {"1": `+metadataLegacyJSON+`}, "users": {"10": {"screen_name": "alice"}}}}`), &timeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res result
+	if err := json.Unmarshal([]byte(`{"__typename": "Tweet", "legacy": `+metadataLegacyJSON+`}`), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tweet := range map[string]*Tweet{"v1": timeline.parseTweet("1"), "v2": res.parse()} {
+		if tweet.Lang != "en" || tweet.Source != "Twitter Web App" || tweet.Quotes != 3 || tweet.Bookmarks != 4 {
+			t.Errorf("%s: unexpected counts and labels %+v", name, tweet)
+		}
+		if !tweet.PossiblySensitive || !tweet.IsQuoted || !tweet.Favorited || tweet.Retweeted {
+			t.Errorf("%s: unexpected flags %+v", name, tweet)
+		}
+		if tweet.ReplySettings != "Community" {
+			t.Errorf("%s: expected reply settings Community, got %q", name, tweet.ReplySettings)
+		}
+		if !reflect.DeepEqual(tweet.WithheldInCountries, []string{"DE", "FR"}) || !reflect.DeepEqual(tweet.DisplayTextRange, []int{5, 10}) {
+			t.Errorf("%s: unexpected withheld countries or display range %+v", name, tweet)
+		}
+	}
+}
+
+func TestParseSourceGraphQL(t *testing.T) {
+	var res result
+	err := json.Unmarshal([]byte(`{"__typename": "Tweet", "source": "<a href=\"http://twitter.com/download/iphone\">Twitter for iPhone</a>", "legacy": {"id_str": "1"}}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source := res.parse().Source; source != "Twitter for iPhone" {
+		t.Errorf("Expected source Twitter for iPhone, got %q", source)
+	}
+}
+
+func TestParseSourceUnescapesName(t *testing.T) {
+	if source := parseSource(`<a href="https://example.com" rel="nofollow">Q&amp;A &lt;bot&gt;</a>`); source != "Q&A <bot>" {
+		t.Errorf("Expected source Q&A <bot>, got %q", source)
+	}
+}
+
+func TestParseSourceSearchAndRetweet(t *testing.T) {
+	source := `<a href="https://mobile.twitter.com" rel="nofollow">Twitter Web App</a>`
+	retweeted := tweetResultJSON("2", "bob")
+	retweeted["source"] = source
+	tweet := tweetResultJSON("1", "alice")
+	tweet["source"] = source
+	tweet["legacy"].(map[string]interface{})["retweeted_status_result"] = map[string]interface{}{"result": retweeted}
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{"search_by_raw_query": map[string]interface{}{
+			"search_timeline": map[string]interface{}{"timeline": map[string]interface{}{"instructions": []interface{}{
+				map[string]interface{}{"type": "TimelineAddEntries", "entries": []interface{}{
+					map[string]interface{}{"entryId": "tweet-1", "content": map[string]interface{}{"itemContent": map[string]interface{}{
+						"tweetDisplayType": "Tweet",
+						"tweet_results":    map[string]interface{}{"result": tweet},
+					}}},
+				}},
+			}}},
+		}}}
+	})
+
+	tweets, _, err := scraper.FetchSearchTweets("golang", 20, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tweets) != 1 || tweets[0].RetweetedStatus == nil {
+		t.Fatalf("Expected a retweet, got %+v", tweets)
+	}
+	for _, tweet := range []*Tweet{tweets[0], tweets[0].RetweetedStatus} {
+		if tweet.Source != "Twitter Web App" {
+			t.Errorf("Tweet %s: expected source Twitter Web App, got %q", tweet.ID, tweet.Source)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package twitterscraper
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// NotificationType is the kind of a notification.
+type NotificationType string
+
+const (
+	// NotificationLike - users liked a tweet of the account
+	NotificationLike NotificationType = "like"
+	// NotificationRetweet - users retweeted a tweet of the account
+	NotificationRetweet NotificationType = "retweet"
+	// NotificationFollow - users followed the account
+	NotificationFollow NotificationType = "follow"
+	// NotificationReply - a user replied to a tweet of the account
+	NotificationReply NotificationType = "reply"
+	// NotificationMention - a user mentioned the account
+	NotificationMention NotificationType = "mention"
+	// NotificationOther - any other notification, like a login alert
+	NotificationOther NotificationType = "other"
+)
+
+type (
+	// Notification of the logged in account.
+	Notification struct {
+		ID         string           `json:"id"`
+		Type       NotificationType `json:"type"`
+		Message    string           `json:"message,omitempty"`
+		TimeParsed time.Time        `json:"-"`
+		Timestamp  int64            `json:"timestamp"`
+		// Tweets the notification is about: the liked or retweeted tweets,
+		// or the reply or mention itself.
+		Tweets []*Tweet `json:"tweets,omitempty"`
+		// Users who triggered the notification.
+		Users []Profile `json:"users,omitempty"`
+	}
+
+	// NotificationResult of scrapping.
+	NotificationResult struct {
+		Notification
+		Error error
+	}
+)
+
+type legacyNotification struct {
+	ID          string `json:"id"`
+	TimestampMs string `json:"timestampMs"`
+	Icon        struct {
+		ID string `json:"id"`
+	} `json:"icon"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Template struct {
+		AggregateUserActionsV1 struct {
+			TargetObjects []struct {
+				Tweet struct {
+					ID string `json:"id"`
+				} `json:"tweet"`
+			} `json:"targetObjects"`
+			FromUsers []struct {
+				User struct {
+					ID string `json:"id"`
+				} `json:"user"`
+			} `json:"fromUsers"`
+		} `json:"aggregateUserActionsV1"`
+	} `json:"template"`
+}
+
+var notificationIcons = map[string]NotificationType{
+	"heart_icon":   NotificationLike,
+	"retweet_icon": NotificationRetweet,
+	"person_icon":  NotificationFollow,
+}
+
+// parseNotifications returns the notifications of a notifications/all
+// timeline. Replies and mentions are timeline tweet entries; the other
+// notifications reference the notifications global objects.
+func (timeline *timelineV1) parseNotifications() ([]*Notification, string) {
+	var cursor string
+	var notifications []*Notification
+	for _, instruction := range timeline.Timeline.Instructions {
+		for _, entry := range instruction.AddEntries.Entries {
+			if entry.Content.Operation.Cursor.CursorType == "Bottom" {
+				cursor = entry.Content.Operation.Cursor.Value
+				continue
+			}
+			if id := entry.Content.Item.Content.Notification.ID; id != "" {
+				if n, ok := timeline.GlobalObjects.Notifications[id]; ok {
+					notifications = append(notifications, timeline.parseNotification(&n))
+				}
+				continue
+			}
+			if tweet := timeline.parseTweet(entry.Content.Item.Content.Tweet.ID); tweet != nil {
+				n := &Notification{
+					ID:         entry.EntryID,
+					Type:       NotificationMention,
+					TimeParsed: tweet.TimeParsed,
+					Timestamp:  tweet.Timestamp,
+					Tweets:     []*Tweet{tweet},
+				}
+				if tweet.IsReply {
+					n.Type = NotificationReply
+				}
+				if user, ok := timeline.GlobalObjects.Users[tweet.UserID]; ok {
+					n.Users = []Profile{parseProfile(user)}
+				}
+				notifications = append(notifications, n)
+			}
+		}
+		if instruction.ReplaceEntry.Entry.Content.Operation.Cursor.CursorType == "Bottom" {
+			cursor = instruction.ReplaceEntry.Entry.Content.Operation.Cursor.Value
+		}
+	}
+	return notifications, cursor
+}
+
+func (timeline *timelineV1) parseNotification(n *legacyNotification) *Notification {
+	notification := &Notification{
+		ID:      n.ID,
+		Type:    NotificationOther,
+		Message: n.Message.Text,
+	}
+	if t, ok := notificationIcons[n.Icon.ID]; ok {
+		notification.Type = t
+	}
+	if ms, err := strconv.ParseInt(n.TimestampMs, 10, 64); err == nil {
+		notification.TimeParsed = time.UnixMilli(ms)
+		notification.Timestamp = notification.TimeParsed.Unix()
+	}
+	for _, target := range n.Template.AggregateUserActionsV1.TargetObjects {
+		if tweet := timeline.parseTweet(target.Tweet.ID); tweet != nil {
+			notification.Tweets = append(notification.Tweets, tweet)
+		}
+	}
+	for _, from := range n.Template.AggregateUserActionsV1.FromUsers {
+		if user, ok := timeline.GlobalObjects.Users[from.User.ID]; ok {
+			notification.Users = append(notification.Users, parseProfile(user))
+		}
+	}
+	return notification
+}
+
+// GetMentions returns channel with tweets mentioning the logged in user.
+// Requires a session logged in with a user account.
+func (s *Scraper) GetMentions(ctx context.Context, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, "", maxTweetsNbr, func(_ string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+		return s.FetchMentions(maxTweetsNbr, cursor)
+	})
+}
+
+// FetchMentions gets tweets mentioning the logged in user, via the Twitter frontend API.
+func (s *Scraper) FetchMentions(maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	timeline, err := s.fetchNotifications("https://twitter.com/i/api/2/notifications/mentions.json", maxTweetsNbr, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	tweets, nextCursor := timeline.parseTweets()
+	return tweets, nextCursor, nil
+}
+
+// GetNotifications returns channel with notifications of the logged in user.
+// Requires a session logged in with a user account.
+func (s *Scraper) GetNotifications(ctx context.Context, maxNotificationsNbr int) <-chan *NotificationResult {
+	channel := make(chan *NotificationResult)
+	go func() {
+		defer close(channel)
+		var cursor string
+		notificationsNbr := 0
+		for notificationsNbr < maxNotificationsNbr {
+			select {
+			case <-ctx.Done():
+				channel <- &NotificationResult{Error: ctx.Err()}
+				return
+			default:
+			}
+
+			notifications, next, err := s.FetchNotifications(maxNotificationsNbr, cursor)
+			if err != nil {
+				channel <- &NotificationResult{Error: err}
+				return
+			}
+			if len(notifications) == 0 || next == cursor {
+				return
+			}
+
+			for _, notification := range notifications {
+				if notificationsNbr >= maxNotificationsNbr {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					channel <- &NotificationResult{Error: ctx.Err()}
+					return
+				case channel <- &NotificationResult{Notification: *notification}:
+				}
+				notificationsNbr++
+			}
+			cursor = next
+		}
+	}()
+	return channel
+}
+
+// FetchNotifications gets notifications of the logged in user, via the Twitter frontend API.
+func (s *Scraper) FetchNotifications(maxNotificationsNbr int, cursor string) ([]*Notification, string, error) {
+	timeline, err := s.fetchNotifications("https://twitter.com/i/api/2/notifications/all.json", maxNotificationsNbr, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	notifications, nextCursor := timeline.parseNotifications()
+	return notifications, nextCursor, nil
+}
+
+func (s *Scraper) fetchNotifications(endpoint string, count int, cursor string) (*timelineV1, error) {
+	if err := s.requireUserSession(); err != nil {
+		return nil, err
+	}
+	if count > 40 {
+		count = 40
+	}
+
+	req, err := s.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("count", strconv.Itoa(count))
+	if cursor != "" {
+		q.Add("cursor", cursor)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	var timeline timelineV1
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, err
+	}
+	return &timeline, nil
+}
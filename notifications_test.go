@@ -0,0 +1,74 @@
+package twitterscraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAccountTimelinesRequireUserSession(t *testing.T) {
+	scraper := New()
+	for result := range scraper.GetBookmarks(context.Background(), 10) {
+		if result.Error != ErrLoginRequired {
+			t.Errorf("Expected ErrLoginRequired, got %v", result.Error)
+		}
+	}
+	for result := range scraper.GetMentions(context.Background(), 10) {
+		if result.Error != ErrLoginRequired {
+			t.Errorf("Expected ErrLoginRequired, got %v", result.Error)
+		}
+	}
+	for result := range scraper.GetNotifications(context.Background(), 10) {
+		if result.Error != ErrLoginRequired {
+			t.Errorf("Expected ErrLoginRequired, got %v", result.Error)
+		}
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	data, err := os.ReadFile("testdata/notifications_all.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if req.URL.Query().Get("cursor") != "" {
+			return map[string]interface{}{}
+		}
+		return json.RawMessage(data)
+	})
+
+	type summary struct {
+		ID     string
+		Type   NotificationType
+		Tweets []string
+		Users  []string
+	}
+	var got []summary
+	for result := range scraper.GetNotifications(context.Background(), 10) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		s := summary{ID: result.ID, Type: result.Type}
+		for _, tweet := range result.Tweets {
+			s.Tweets = append(s.Tweets, tweet.ID)
+		}
+		for _, user := range result.Users {
+			s.Users = append(s.Users, user.Username)
+		}
+		got = append(got, s)
+	}
+	expected := []summary{
+		{ID: "n1", Type: NotificationLike, Tweets: []string{"10"}, Users: []string{"bob", "carol"}},
+		{ID: "notification-11", Type: NotificationReply, Tweets: []string{"11"}, Users: []string{"bob"}},
+		{ID: "n2", Type: NotificationFollow, Users: []string{"carol"}},
+		{ID: "notification-12", Type: NotificationMention, Tweets: []string{"12"}, Users: []string{"carol"}},
+		{ID: "n3", Type: NotificationOther},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Error("Notifications do not match", diff)
+	}
+}
@@ -2,45 +2,111 @@ package twitterscraper
 
 import (
 	"fmt"
-	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
 
-// Global cache for user IDs
-var cacheIDs sync.Map
+// Global cache of user IDs by lower-cased screen name, and of screen names by
+// user ID. The two maps are kept consistent when a user renames.
+var (
+	cacheIDs   sync.Map
+	cacheNames sync.Map
+	cacheMu    sync.Mutex
+)
+
+// cacheUser records the current screen name of a user. The mapping of a
+// previous screen name of the user is dropped.
+func cacheUser(userID, screenName string) {
+	if userID == "" || screenName == "" {
+		return
+	}
+	name := strings.ToLower(screenName)
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if old, ok := cacheNames.Load(userID); ok {
+		if oldName := strings.ToLower(old.(string)); oldName != name {
+			if id, ok := cacheIDs.Load(oldName); ok && id.(string) == userID {
+				cacheIDs.Delete(oldName)
+			}
+		}
+	}
+	if id, ok := cacheIDs.Load(name); ok && id.(string) != userID {
+		// The screen name was taken over by another user.
+		cacheNames.Delete(id)
+	}
+	cacheIDs.Store(name, userID)
+	cacheNames.Store(userID, screenName)
+}
 
-// Profile of twitter user.
+// Profile of twitter user. See MarshalJSON for its JSON schema.
 type Profile struct {
-	Avatar         string
-	Banner         string
-	Biography      string
-	Birthday       string
-	FollowersCount int
-	FollowingCount int
-	FriendsCount   int
-	IsPrivate      bool
-	IsVerified     bool
-	Joined         *time.Time
-	LikesCount     int
-	ListedCount    int
-	Location       string
-	Name           string
-	PinnedTweetIDs []string
-	TweetsCount    int
-	URL            string
-	UserID         string
-	Username       string
-	Website        string
+	Affiliation *Affiliation `json:"affiliation,omitempty"`
+	Avatar      string       `json:"avatar,omitempty"`
+	Banner      string       `json:"banner,omitempty"`
+	Biography   string       `json:"biography,omitempty"`
+	// BiographyURLs are the links of Biography.
+	BiographyURLs []URLEntity `json:"biography_urls,omitempty"`
+	// Birthday is formatted as YYYY-MM-DD, or MM-DD when the user hides the
+	// year. It is only set when the user shares it.
+	Birthday            string `json:"birthday,omitempty"`
+	CanDM               bool   `json:"can_dm"`
+	DefaultProfileImage bool   `json:"default_profile_image"`
+	// FastFollowersCount and NormalFollowersCount split FollowersCount, when
+	// Twitter returns them.
+	FastFollowersCount int `json:"fast_followers_count"`
+	FollowersCount     int `json:"followers_count"`
+	// FollowingCount is the number of accounts the user follows. Profiles
+	// encoded with schema version 1 held LikesCount instead; UnmarshalJSON
+	// restores it from FriendsCount.
+	FollowingCount int `json:"following_count"`
+	// Deprecated: FriendsCount is FollowingCount under its Twitter API name.
+	// Use FollowingCount.
+	FriendsCount int `json:"friends_count"`
+	// IsBlueVerified is set for paid verification.
+	IsBlueVerified bool `json:"is_blue_verified"`
+	IsPrivate      bool `json:"is_private"`
+	// IsVerified is set for legacy verification only. Combine it with
+	// IsBlueVerified and VerifiedType for the other kinds.
+	IsVerified           bool       `json:"is_verified"`
+	Joined               *time.Time `json:"joined,omitempty"`
+	LikesCount           int        `json:"likes_count"`
+	ListedCount          int        `json:"listed_count"`
+	Location             string     `json:"location,omitempty"`
+	MediaCount           int        `json:"media_count"`
+	Name                 string     `json:"name"`
+	NormalFollowersCount int        `json:"normal_followers_count"`
+	PinnedTweetIDs       []string   `json:"pinned_tweet_ids,omitempty"`
+	// ProfessionalType is "Business" or "Creator" for professional accounts,
+	// with ProfessionalCategory their category, like "Media & News".
+	ProfessionalCategory string `json:"professional_category,omitempty"`
+	ProfessionalType     string `json:"professional_type,omitempty"`
+	TweetsCount          int    `json:"tweets_count"`
+	URL                  string `json:"url"`
+	UserID               string `json:"user_id"`
+	Username             string `json:"username"`
+	// VerifiedType is "Business" or "Government" for verified organizations
+	// and their affiliates.
+	VerifiedType        string   `json:"verified_type,omitempty"`
+	Website             string   `json:"website,omitempty"`
+	WithheldInCountries []string `json:"withheld_in_countries,omitempty"`
+}
+
+// Affiliation is the badge of an account affiliated with an organization.
+type Affiliation struct {
+	// Description is the name of the organization.
+	Description string `json:"description"`
+	BadgeURL    string `json:"badge_url,omitempty"`
+	// URL is the profile of the organization.
+	URL string `json:"url,omitempty"`
+	// Type is the kind of label, like "BusinessLabel".
+	Type string `json:"type,omitempty"`
 }
 
 type user struct {
 	Data struct {
-		User struct {
-			RestID string     `json:"rest_id"`
-			Legacy legacyUser `json:"legacy"`
-		} `json:"user"`
+		User userResult `json:"user"`
 	} `json:"data"`
 	Errors []struct {
 		Message string `json:"message"`
@@ -50,21 +116,11 @@ type user struct {
 // GetProfile return parsed user profile.
 func (s *Scraper) GetProfile(username string) (Profile, error) {
 	var jsn user
-	req, err := http.NewRequest("GET", "https://api.twitter.com/graphql/4S2ihIKfF3xhp-ENxvUAfQ/UserByScreenName", nil)
-	if err != nil {
-		return Profile{}, err
-	}
-
-	variables := map[string]interface{}{
-		"screen_name":          username,
-		"withHighlightedLabel": true,
-	}
-
-	query := url.Values{}
-	query.Set("variables", mapToJSONString(variables))
-	req.URL.RawQuery = query.Encode()
-
-	err = s.RequestAPI(req, &jsn)
+	err := s.requestUsers("https://api.twitter.com/graphql/4S2ihIKfF3xhp-ENxvUAfQ/UserByScreenName", map[string]interface{}{
+		"screen_name":              username,
+		"withHighlightedLabel":     true,
+		"withSafetyModeUserFields": true,
+	}, &jsn)
 	if err != nil {
 		return Profile{}, err
 	}
@@ -76,18 +132,19 @@ func (s *Scraper) GetProfile(username string) (Profile, error) {
 	if jsn.Data.User.RestID == "" {
 		return Profile{}, fmt.Errorf("rest_id not found")
 	}
-	jsn.Data.User.Legacy.IDStr = jsn.Data.User.RestID
 
 	if jsn.Data.User.Legacy.ScreenName == "" {
 		return Profile{}, fmt.Errorf("either @%s does not exist or is private", username)
 	}
 
-	return parseProfile(jsn.Data.User.Legacy), nil
+	profile := jsn.Data.User.profile()
+	cacheUser(profile.UserID, profile.Username)
+	return profile, nil
 }
 
 // GetUserIDByScreenName from API
 func (s *Scraper) GetUserIDByScreenName(screenName string) (string, error) {
-	id, ok := cacheIDs.Load(screenName)
+	id, ok := cacheIDs.Load(strings.ToLower(screenName))
 	if ok {
 		return id.(string), nil
 	}
@@ -97,7 +154,270 @@ func (s *Scraper) GetUserIDByScreenName(screenName string) (string, error) {
 		return "", err
 	}
 
-	cacheIDs.Store(screenName, profile.UserID)
-
 	return profile.UserID, nil
 }
+
+// GetScreenNameByUserID from API
+func (s *Scraper) GetScreenNameByUserID(userID string) (string, error) {
+	name, ok := cacheNames.Load(userID)
+	if ok {
+		return name.(string), nil
+	}
+
+	profile, err := s.GetProfileByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	return profile.Username, nil
+}
+
+// parseProfile maps a legacy user object to a Profile. It is the single
+// place the counters and flags of users are mapped: the GraphQL API, through
+// userResult.profile, and the v1 API share it.
+func parseProfile(user legacyUser) Profile {
+	profile := Profile{
+		Avatar:               user.ProfileImageURLHTTPS,
+		Banner:               user.ProfileBannerURL,
+		Biography:            user.Description,
+		CanDM:                user.CanDM,
+		DefaultProfileImage:  user.DefaultProfileImage,
+		FollowersCount:       user.FollowersCount,
+		FastFollowersCount:   user.FastFollowersCount,
+		FollowingCount:       user.FriendsCount,
+		FriendsCount:         user.FriendsCount,
+		IsBlueVerified:       user.IsBlueVerified || user.ExtIsBlueVerified,
+		IsPrivate:            user.Protected,
+		IsVerified:           user.Verified,
+		LikesCount:           user.FavouritesCount,
+		ListedCount:          user.ListedCount,
+		Location:             user.Location,
+		MediaCount:           user.MediaCount,
+		Name:                 user.Name,
+		NormalFollowersCount: user.NormalFollowersCount,
+		PinnedTweetIDs:       user.PinnedTweetIdsStr,
+		TweetsCount:          user.StatusesCount,
+		URL:                  "https://twitter.com/" + user.ScreenName,
+		UserID:               user.IDStr,
+		Username:             user.ScreenName,
+		VerifiedType:         user.VerifiedType,
+		WithheldInCountries:  user.WithheldInCountries,
+	}
+
+	tm, err := time.Parse(time.RubyDate, user.CreatedAt)
+	if err == nil {
+		tm = tm.UTC()
+		profile.Joined = &tm
+	}
+
+	if len(user.Entities.URL.Urls) > 0 {
+		profile.Website = user.Entities.URL.Urls[0].ExpandedURL
+	}
+	for _, u := range user.Entities.Description.Urls {
+		profile.BiographyURLs = append(profile.BiographyURLs, URLEntity{
+			URL:         u.URL,
+			ExpandedURL: u.ExpandedURL,
+			DisplayURL:  u.DisplayURL,
+		})
+	}
+
+	return profile
+}
+
+// userResult is a user of the GraphQL API.
+type userResult struct {
+	Typename       string     `json:"__typename"`
+	RestID         string     `json:"rest_id"`
+	IsBlueVerified bool       `json:"is_blue_verified"`
+	Legacy         legacyUser `json:"legacy"`
+	Reason         string     `json:"reason"`
+
+	AffiliatesHighlightedLabel struct {
+		Label struct {
+			Badge struct {
+				URL string `json:"url"`
+			} `json:"badge"`
+			Description string `json:"description"`
+			URL         struct {
+				URL string `json:"url"`
+			} `json:"url"`
+			UserLabelType string `json:"userLabelType"`
+		} `json:"label"`
+	} `json:"affiliates_highlighted_label"`
+	Professional struct {
+		ProfessionalType string `json:"professional_type"`
+		Category         []struct {
+			Name string `json:"name"`
+		} `json:"category"`
+	} `json:"professional"`
+	LegacyExtendedProfile struct {
+		Birthdate struct {
+			Day   int `json:"day"`
+			Month int `json:"month"`
+			Year  int `json:"year"`
+		} `json:"birthdate"`
+	} `json:"legacy_extended_profile"`
+}
+
+// profile maps the user to a Profile, adding the fields the GraphQL API
+// returns next to the legacy ones.
+func (result *userResult) profile() Profile {
+	if result.RestID != "" {
+		result.Legacy.IDStr = result.RestID
+	}
+	result.Legacy.IsBlueVerified = result.Legacy.IsBlueVerified || result.IsBlueVerified
+	profile := parseProfile(result.Legacy)
+
+	if label := result.AffiliatesHighlightedLabel.Label; label.Description != "" {
+		profile.Affiliation = &Affiliation{
+			Description: label.Description,
+			BadgeURL:    label.Badge.URL,
+			URL:         label.URL.URL,
+			Type:        label.UserLabelType,
+		}
+	}
+	profile.ProfessionalType = result.Professional.ProfessionalType
+	if len(result.Professional.Category) > 0 {
+		profile.ProfessionalCategory = result.Professional.Category[0].Name
+	}
+	if birthdate := result.LegacyExtendedProfile.Birthdate; birthdate.Month > 0 && birthdate.Day > 0 {
+		if birthdate.Year > 0 {
+			profile.Birthday = fmt.Sprintf("%04d-%02d-%02d", birthdate.Year, birthdate.Month, birthdate.Day)
+		} else {
+			profile.Birthday = fmt.Sprintf("%02d-%02d", birthdate.Month, birthdate.Day)
+		}
+	}
+	return profile
+}
+
+func (result *userResult) parse() (Profile, error) {
+	if result.Typename == "UserUnavailable" {
+		if result.Reason != "" {
+			return Profile{}, fmt.Errorf("user unavailable: %s", result.Reason)
+		}
+		return Profile{}, fmt.Errorf("user unavailable")
+	}
+	if result.RestID == "" || result.Legacy.ScreenName == "" {
+		return Profile{}, fmt.Errorf("user not found")
+	}
+	profile := result.profile()
+	cacheUser(profile.UserID, profile.Username)
+	return profile, nil
+}
+
+// GetProfileByID return parsed user profile for a given user ID.
+func (s *Scraper) GetProfileByID(userID string) (Profile, error) {
+	var jsn struct {
+		Data struct {
+			User struct {
+				Result *userResult `json:"result"`
+			} `json:"user"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err := s.requestUsers("https://twitter.com/i/api/graphql/tD8zKvQzwY3kdx5yz6YmOw/UserByRestId", map[string]interface{}{
+		"userId":                   userID,
+		"withSafetyModeUserFields": true,
+	}, &jsn)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if len(jsn.Errors) > 0 {
+		return Profile{}, fmt.Errorf("%s", jsn.Errors[0].Message)
+	}
+	if jsn.Data.User.Result == nil {
+		return Profile{}, fmt.Errorf("user with ID %s not found", userID)
+	}
+	profile, err := jsn.Data.User.Result.parse()
+	if err != nil {
+		return Profile{}, fmt.Errorf("user with ID %s: %w", userID, err)
+	}
+	return profile, nil
+}
+
+// maxUsersByRestIds is the number of users UsersByRestIds returns at most.
+const maxUsersByRestIds = 100
+
+// GetProfiles return parsed user profiles for given user IDs, in the same
+// order. Users that can't be loaded (suspended, deactivated, unknown) have
+// Error set in their result; the returned error is set only when a request
+// fails.
+func (s *Scraper) GetProfiles(userIDs []string) ([]ProfileResult, error) {
+	results := make([]ProfileResult, len(userIDs))
+	for start := 0; start < len(userIDs); start += maxUsersByRestIds {
+		end := start + maxUsersByRestIds
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		var jsn struct {
+			Data struct {
+				Users []struct {
+					Result *userResult `json:"result"`
+				} `json:"users"`
+			} `json:"data"`
+		}
+		err := s.requestUsers("https://twitter.com/i/api/graphql/itEhGywpgX9b3GJCzOtSrA/UsersByRestIds", map[string]interface{}{
+			"userIds": userIDs[start:end],
+		}, &jsn)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles := make(map[string]Profile)
+		errs := make(map[string]error)
+		for _, user := range jsn.Data.Users {
+			if user.Result == nil {
+				continue
+			}
+			profile, err := user.Result.parse()
+			if err != nil {
+				errs[user.Result.RestID] = err
+				continue
+			}
+			profiles[profile.UserID] = profile
+		}
+		for i, id := range userIDs[start:end] {
+			if profile, ok := profiles[id]; ok {
+				results[start+i] = ProfileResult{Profile: profile}
+			} else if err, ok := errs[id]; ok {
+				results[start+i] = ProfileResult{Profile: Profile{UserID: id}, Error: fmt.Errorf("user with ID %s: %w", id, err)}
+			} else {
+				results[start+i] = ProfileResult{Profile: Profile{UserID: id}, Error: fmt.Errorf("user with ID %s not found", id)}
+			}
+		}
+	}
+	return results, nil
+}
+
+func (s *Scraper) requestUsers(endpoint string, variables map[string]interface{}, target interface{}) error {
+	req, err := s.newRequest("GET", endpoint)
+	if err != nil {
+		return err
+	}
+
+	features := map[string]interface{}{
+		"hidden_profile_likes_enabled":                                      true,
+		"hidden_profile_subscriptions_enabled":                              true,
+		"rweb_tipjar_consumption_enabled":                                   true,
+		"responsive_web_graphql_exclude_directive_enabled":                  true,
+		"verified_phone_label_enabled":                                      false,
+		"subscriptions_verification_info_is_identity_verified_enabled":      true,
+		"subscriptions_verification_info_verified_since_enabled":            true,
+		"highlights_tweets_tab_ui_enabled":                                  true,
+		"responsive_web_twitter_article_notes_tab_enabled":                  true,
+		"creator_subscriptions_tweet_preview_api_enabled":                   true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled": false,
+		"responsive_web_graphql_timeline_navigation_enabled":                true,
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	return s.RequestAPI(req, target)
+}
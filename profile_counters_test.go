@@ -0,0 +1,157 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// counters are the counters of testdata/user_counters.json, each with a
+// distinct value so that a counter mapped from the wrong field is caught.
+type counters struct {
+	Followers, NormalFollowers, FastFollowers, Following, Friends, Likes, Listed, Tweets, Media int
+}
+
+var wantCounters = counters{
+	Followers:       1100,
+	NormalFollowers: 1000,
+	FastFollowers:   100,
+	Following:       220,
+	Friends:         220,
+	Likes:           330,
+	Listed:          44,
+	Tweets:          550,
+	Media:           66,
+}
+
+func profileCounters(profile *Profile) counters {
+	return counters{
+		Followers:       profile.FollowersCount,
+		NormalFollowers: profile.NormalFollowersCount,
+		FastFollowers:   profile.FastFollowersCount,
+		Following:       profile.FollowingCount,
+		Friends:         profile.FriendsCount,
+		Likes:           profile.LikesCount,
+		Listed:          profile.ListedCount,
+		Tweets:          profile.TweetsCount,
+		Media:           profile.MediaCount,
+	}
+}
+
+func loadUserCountersFixture(t *testing.T) json.RawMessage {
+	data, err := os.ReadFile("testdata/user_counters.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cacheIDs.Delete("counter")
+		cacheNames.Delete("42")
+	})
+	return data
+}
+
+// decodeFixture decodes a response built around the user fixture.
+func decodeFixture(t *testing.T, format string, user json.RawMessage, target interface{}) {
+	if err := json.Unmarshal([]byte(fmt.Sprintf(format, user)), target); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkCounters(t *testing.T, path string, profiles []*Profile) {
+	t.Helper()
+	if len(profiles) != 1 {
+		t.Fatalf("%s: expected 1 profile, got %d", path, len(profiles))
+	}
+	if profiles[0].UserID != "42" || profiles[0].Username != "counter" {
+		t.Errorf("%s: unexpected profile %+v", path, profiles[0])
+	}
+	if diff := cmp.Diff(wantCounters, profileCounters(profiles[0])); diff != "" {
+		t.Errorf("%s: counters do not match the fixture %s", path, diff)
+	}
+}
+
+func TestProfileCountersGetProfile(t *testing.T) {
+	user := loadUserCountersFixture(t)
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		return json.RawMessage(fmt.Sprintf(`{"data": {"user": {"rest_id": "42", "legacy": %s}}}`, user))
+	})
+	profile, err := scraper.GetProfile("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCounters(t, "GetProfile", []*Profile{&profile})
+}
+
+func TestProfileCountersGetProfiles(t *testing.T) {
+	user := loadUserCountersFixture(t)
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		return json.RawMessage(fmt.Sprintf(`{"data": {"users": [{"result": {"__typename": "User", "rest_id": "42", "legacy": %s}}]}}`, user))
+	})
+	results, err := scraper.GetProfiles([]string{"42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCounters(t, "GetProfiles", []*Profile{&results[0].Profile})
+}
+
+func TestProfileCountersSearch(t *testing.T) {
+	var timeline searchTimeline
+	decodeFixture(t, `{"data": {"search_by_raw_query": {"search_timeline": {"timeline": {"instructions": [{
+		"type": "TimelineAddEntries",
+		"entries": [{"content": {"itemContent": {"userDisplayType": "User", "user_results": {"result": {"rest_id": "42", "legacy": %s}}}}}]
+	}]}}}}}`, loadUserCountersFixture(t), &timeline)
+	profiles, _ := timeline.parseUsers()
+	checkCounters(t, "search", profiles)
+}
+
+func TestProfileCountersFollowers(t *testing.T) {
+	user := loadUserCountersFixture(t)
+	cacheUser("7", "followed")
+	defer func() {
+		cacheIDs.Delete("followed")
+		cacheNames.Delete("7")
+	}()
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if id := requestVariables(t, req)["userId"]; id != "7" {
+			t.Errorf("Expected userId 7, got %v", id)
+		}
+		return json.RawMessage(fmt.Sprintf(`{"data": {"user": {"result": {"timeline": {"timeline": {"instructions": [{
+			"type": "TimelineAddEntries",
+			"entries": [
+				{"content": {"itemContent": {"user_results": {"result": {"rest_id": "42", "legacy": %s}}}}},
+				{"content": {"cursorType": "Bottom", "value": "next-page"}}
+			]
+		}]}}}}}}`, user))
+	})
+	profiles, cursor, err := scraper.FetchFollowerProfiles("followed", 20, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor != "next-page" {
+		t.Errorf("Expected cursor next-page, got %q", cursor)
+	}
+	checkCounters(t, "followers", profiles)
+
+	// The deprecated FetchFollowers still returns the raw objects.
+	legacies, _, err := scraper.FetchFollowers("followed", 20, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(legacies) == 0 || legacies[0].FriendsCount != 220 || legacies[0].NormalFollowersCount != 1000 {
+		t.Errorf("Unexpected followers %+v", legacies)
+	}
+}
+
+func TestProfileCountersV1(t *testing.T) {
+	var timeline timelineV1
+	decodeFixture(t, `{
+		"globalObjects": {"users": {"42": %s}},
+		"timeline": {"instructions": [{"addEntries": {"entries": [{"content": {"item": {"content": {"user": {"id": "42"}}}}}]}}]}
+	}`, loadUserCountersFixture(t), &timeline)
+	profiles, _ := timeline.parseUsers()
+	checkCounters(t, "v1", profiles)
+}
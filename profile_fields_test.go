@@ -0,0 +1,98 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// userByScreenNameFixture returns a fake scraper answering every request
+// with testdata/user_by_screen_name.json.
+func userByScreenNameFixture(t *testing.T, check func(req *http.Request)) *Scraper {
+	data, err := os.ReadFile("testdata/user_by_screen_name.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cacheIDs.Delete("x")
+		cacheNames.Delete("783214")
+	})
+	return newFakeScraper(t, func(req *http.Request) interface{} {
+		if check != nil {
+			check(req)
+		}
+		return json.RawMessage(data)
+	})
+}
+
+func TestGetProfileFields(t *testing.T) {
+	scraper := userByScreenNameFixture(t, func(req *http.Request) {
+		var features map[string]interface{}
+		if err := json.Unmarshal([]byte(req.URL.Query().Get("features")), &features); err != nil {
+			t.Fatal(err)
+		}
+		if features["hidden_profile_likes_enabled"] != true || features["subscriptions_verification_info_is_identity_verified_enabled"] != true {
+			t.Errorf("Expected profile features to be requested, got %v", features)
+		}
+		if variables := requestVariables(t, req); variables["screen_name"] != "X" {
+			t.Errorf("Expected screen_name X, got %v", variables)
+		}
+	})
+	profile, err := scraper.GetProfile("X")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Profile{
+		Affiliation: &Affiliation{
+			Description: "X Corp",
+			BadgeURL:    "https://pbs.twimg.com/profile_images/1/badge_bigger.jpg",
+			URL:         "https://twitter.com/XCorp",
+			Type:        "BusinessLabel",
+		},
+		BiographyURLs:        []URLEntity{{URL: "https://t.co/bio", ExpandedURL: "https://help.x.com", DisplayURL: "help.x.com"}},
+		Birthday:             "2006-03-21",
+		CanDM:                true,
+		IsBlueVerified:       true,
+		MediaCount:           2821,
+		ProfessionalCategory: "Media & News",
+		ProfessionalType:     "Business",
+		UserID:               "783214",
+		Username:             "X",
+		VerifiedType:         "Business",
+		WithheldInCountries:  []string{"TR"},
+	}
+	got := Profile{
+		Affiliation:          profile.Affiliation,
+		BiographyURLs:        profile.BiographyURLs,
+		Birthday:             profile.Birthday,
+		CanDM:                profile.CanDM,
+		DefaultProfileImage:  profile.DefaultProfileImage,
+		IsBlueVerified:       profile.IsBlueVerified,
+		IsVerified:           profile.IsVerified,
+		MediaCount:           profile.MediaCount,
+		ProfessionalCategory: profile.ProfessionalCategory,
+		ProfessionalType:     profile.ProfessionalType,
+		UserID:               profile.UserID,
+		Username:             profile.Username,
+		VerifiedType:         profile.VerifiedType,
+		WithheldInCountries:  profile.WithheldInCountries,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Resulting profile does not match the sample", diff)
+	}
+}
+
+func TestBirthdayWithoutYear(t *testing.T) {
+	var result userResult
+	err := json.Unmarshal([]byte(`{"rest_id": "1", "legacy": {"screen_name": "a"}, "legacy_extended_profile": {"birthdate": {"day": 5, "month": 11, "visibility": "Self"}}}`), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if birthday := result.profile().Birthday; birthday != "11-05" {
+		t.Errorf("Expected birthday 11-05, got %q", birthday)
+	}
+}
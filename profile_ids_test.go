@@ -0,0 +1,85 @@
+package twitterscraper
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCacheUserTracksRenames(t *testing.T) {
+	defer func() {
+		cacheIDs.Delete("old_name")
+		cacheIDs.Delete("new_name")
+		cacheNames.Delete("rename-1")
+	}()
+	cacheUser("rename-1", "Old_Name")
+	scraper := New()
+	if id, err := scraper.GetUserIDByScreenName("old_name"); err != nil || id != "rename-1" {
+		t.Fatalf("Expected cached ID rename-1, got %q %v", id, err)
+	}
+	cacheUser("rename-1", "New_Name")
+	if _, ok := cacheIDs.Load("old_name"); ok {
+		t.Error("Expected the previous screen name dropped from the cache")
+	}
+	if name, err := scraper.GetScreenNameByUserID("rename-1"); err != nil || name != "New_Name" {
+		t.Errorf("Expected cached screen name New_Name, got %q %v", name, err)
+	}
+}
+
+func TestGetProfileByID(t *testing.T) {
+	defer func() {
+		cacheIDs.Delete("by_id")
+		cacheNames.Delete("123")
+	}()
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if id := requestVariables(t, req)["userId"]; id != "123" {
+			t.Errorf("Expected userId 123, got %v", id)
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"result": userJSON("123", "by_id")}}}
+	})
+	profile, err := scraper.GetProfileByID("123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.UserID != "123" || profile.Username != "by_id" {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+	if id, _ := cacheIDs.Load("by_id"); id != "123" {
+		t.Errorf("Expected profile cached, got %v", id)
+	}
+}
+
+func TestGetProfiles(t *testing.T) {
+	defer func() {
+		for _, name := range []string{"first", "second"} {
+			cacheIDs.Delete(name)
+		}
+		cacheNames.Delete("1")
+		cacheNames.Delete("3")
+	}()
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{"users": []interface{}{
+			map[string]interface{}{"result": userJSON("3", "second")},
+			map[string]interface{}{"result": map[string]interface{}{"__typename": "UserUnavailable", "rest_id": "2", "reason": "Suspended"}},
+			map[string]interface{}{"result": userJSON("1", "first")},
+		}}}
+	})
+	results, err := scraper.GetProfiles([]string{"1", "2", "3", "4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+	if results[0].Error != nil || results[0].Username != "first" {
+		t.Errorf("Unexpected first result %+v", results[0])
+	}
+	if results[1].Error == nil || results[1].Error.Error() != "user with ID 2: user unavailable: Suspended" {
+		t.Errorf("Expected suspended error, got %v", results[1].Error)
+	}
+	if results[2].Error != nil || results[2].Username != "second" {
+		t.Errorf("Unexpected third result %+v", results[2])
+	}
+	if results[3].Error == nil || results[3].UserID != "4" {
+		t.Errorf("Expected not found error for 4, got %+v", results[3])
+	}
+}
@@ -0,0 +1,112 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetTweetsAndReplies returns channel with tweets and replies for a given user,
+// as listed in the "Replies" tab of the profile.
+func (s *Scraper) GetTweetsAndReplies(ctx context.Context, user string, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, user, maxTweetsNbr, s.FetchTweetsAndReplies)
+}
+
+// FetchTweetsAndReplies gets tweets and replies for a given user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchTweetsAndReplies(user string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	return s.fetchUserTimeline("https://twitter.com/i/api/graphql/bt4TKuFz4T7Ckk-VvQVSow/UserTweetsAndReplies", user, maxTweetsNbr, cursor)
+}
+
+// GetMediaTweets returns channel with tweets with photos or videos for a
+// given user, as listed in the "Media" tab of the profile.
+func (s *Scraper) GetMediaTweets(ctx context.Context, user string, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, user, maxTweetsNbr, s.FetchMediaTweets)
+}
+
+// FetchMediaTweets gets tweets with media for a given user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchMediaTweets(user string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	return s.fetchUserTimeline("https://twitter.com/i/api/graphql/dexO_2tohK86JDudXXG3Yw/UserMedia", user, maxTweetsNbr, cursor)
+}
+
+// GetHighlights returns channel with the tweets a given user highlighted on
+// the profile.
+func (s *Scraper) GetHighlights(ctx context.Context, user string, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, user, maxTweetsNbr, s.FetchHighlights)
+}
+
+// FetchHighlights gets highlighted tweets for a given user, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchHighlights(user string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	return s.fetchUserTimeline("https://twitter.com/i/api/graphql/tHFm_XZc_NNi-CfUThwbNw/UserHighlightsTweets", user, maxTweetsNbr, cursor)
+}
+
+// fetchUserTimeline gets a page of a profile tab. Tweets grouped in modules,
+// like the conversations of the replies tab or the media grid, are included.
+func (s *Scraper) fetchUserTimeline(endpoint string, user string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	if maxTweetsNbr > 200 {
+		maxTweetsNbr = 200
+	}
+
+	userID, err := s.GetUserIDByScreenName(user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := s.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	variables := map[string]interface{}{
+		"userId":                 userID,
+		"count":                  maxTweetsNbr,
+		"includePromotedContent": false,
+		"withCommunity":          true,
+		"withClientEventToken":   false,
+		"withBirdwatchNotes":     false,
+		"withVoice":              true,
+		"withV2Timeline":         true,
+	}
+	features := map[string]interface{}{
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var timeline timelineV2
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tweets, nextCursor := parseTimelineTweets(timeline.instructions())
+	return tweets, nextCursor, nil
+}
@@ -0,0 +1,95 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func tweetItem(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"entryId": "profile-grid-0-tweet-" + id,
+		"item": map[string]interface{}{"itemContent": map[string]interface{}{
+			"tweet_results": map[string]interface{}{"result": tweetResultJSON(id, "alice")},
+		}},
+	}
+}
+
+func bottomCursor(value string) map[string]interface{} {
+	return map[string]interface{}{
+		"entryId": "cursor-bottom-" + value,
+		"content": map[string]interface{}{"cursorType": "Bottom", "value": value},
+	}
+}
+
+func TestGetMediaTweets(t *testing.T) {
+	cacheIDs.Store("media_user", "id-media")
+	defer cacheIDs.Delete("media_user")
+	pages := map[string][]interface{}{
+		// The first page holds the media grid as a module entry.
+		"": {map[string]interface{}{"type": "TimelineAddEntries", "entries": []interface{}{
+			map[string]interface{}{
+				"entryId": "profile-grid-0",
+				"content": map[string]interface{}{"items": []interface{}{tweetItem("1"), tweetItem("2")}},
+			},
+			bottomCursor("c1"),
+		}}},
+		// Next pages append to the grid module.
+		"c1": {
+			map[string]interface{}{"type": "TimelineAddToModule", "moduleEntryId": "profile-grid-0", "moduleItems": []interface{}{tweetItem("3")}},
+			map[string]interface{}{"type": "TimelineAddEntries", "entries": []interface{}{bottomCursor("c2")}},
+		},
+		"c2": {map[string]interface{}{"type": "TimelineAddEntries", "entries": []interface{}{bottomCursor("c3")}}},
+	}
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if !strings.HasSuffix(req.URL.Path, "/UserMedia") {
+			t.Errorf("Expected UserMedia request, got %s", req.URL.Path)
+		}
+		cursor, _ := requestVariables(t, req)["cursor"].(string)
+		return map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"result": map[string]interface{}{
+			"timeline_v2": map[string]interface{}{"timeline": map[string]interface{}{"instructions": pages[cursor]}},
+		}}}}
+	})
+
+	var ids []string
+	for result := range scraper.GetMediaTweets(context.Background(), "media_user", 10) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		ids = append(ids, result.ID)
+	}
+	if diff := cmp.Diff([]string{"1", "2", "3"}, ids); diff != "" {
+		t.Error("Media tweets do not match", diff)
+	}
+}
+
+func TestGetHighlights(t *testing.T) {
+	cacheIDs.Store("highlights_user", "id-highlights")
+	defer cacheIDs.Delete("highlights_user")
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		if !strings.HasSuffix(req.URL.Path, "/UserHighlightsTweets") {
+			t.Errorf("Expected UserHighlightsTweets request, got %s", req.URL.Path)
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"result": map[string]interface{}{
+			"timeline": map[string]interface{}{"timeline": map[string]interface{}{"instructions": []interface{}{
+				map[string]interface{}{"type": "TimelineAddEntries", "entries": []interface{}{
+					map[string]interface{}{"entryId": "tweet-7", "content": tweetItem("7")["item"]},
+				}},
+			}}},
+		}}}}
+	})
+
+	var ids []string
+	for result := range scraper.GetHighlights(context.Background(), "highlights_user", 1) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		ids = append(ids, result.ID)
+	}
+	if diff := cmp.Diff([]string{"7"}, ids); diff != "" {
+		t.Error("Highlights do not match", diff)
+	}
+}
@@ -67,6 +67,7 @@ func TestGetProfile(t *testing.T) {
 		cmpopts.IgnoreFields(twitterscraper.Profile{}, "LikesCount"),
 		cmpopts.IgnoreFields(twitterscraper.Profile{}, "ListedCount"),
 		cmpopts.IgnoreFields(twitterscraper.Profile{}, "TweetsCount"),
+		cmpopts.IgnoreFields(twitterscraper.Profile{}, "MediaCount", "CanDM", "BiographyURLs"),
 	}
 	if diff := cmp.Diff(sample, profile, cmpOptions...); diff != "" {
 		t.Error("Resulting profile does not match the sample", diff)
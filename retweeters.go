@@ -0,0 +1,93 @@
+package twitterscraper
+
+import (
+	"context"
+	"net/url"
+)
+
+type retweetersTimeline struct {
+	Data struct {
+		RetweetersTimeline struct {
+			Timeline struct {
+				Instructions []instruction `json:"instructions"`
+			} `json:"timeline"`
+		} `json:"retweeters_timeline"`
+	} `json:"data"`
+}
+
+// GetQuoteTweets returns channel with tweets quoting a given tweet.
+func (s *Scraper) GetQuoteTweets(ctx context.Context, tweetID string, maxTweetsNbr int) <-chan *TweetResult {
+	return getTweetTimeline(ctx, tweetID, maxTweetsNbr, s.FetchQuoteTweets)
+}
+
+// FetchQuoteTweets gets tweets quoting a given tweet, via the Twitter frontend search API.
+func (s *Scraper) FetchQuoteTweets(tweetID string, maxTweetsNbr int, cursor string) ([]*Tweet, string, error) {
+	return s.FetchSearchTweets("quoted_tweet_id:"+tweetID, maxTweetsNbr, cursor)
+}
+
+// GetRetweeters returns channel with profiles of users who retweeted a given tweet.
+func (s *Scraper) GetRetweeters(ctx context.Context, tweetID string, maxProfilesNbr int) <-chan *ProfileResult {
+	return getUserTimeline(ctx, tweetID, maxProfilesNbr, s.FetchRetweeters)
+}
+
+// FetchRetweeters gets users who retweeted a given tweet, via the Twitter frontend GraphQL API.
+func (s *Scraper) FetchRetweeters(tweetID string, maxProfilesNbr int, cursor string) ([]*Profile, string, error) {
+	if maxProfilesNbr > 100 {
+		maxProfilesNbr = 100
+	}
+
+	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/0BoJlKAxoNPQUHRftlwZ2w/Retweeters")
+	if err != nil {
+		return nil, "", err
+	}
+
+	variables := map[string]interface{}{
+		"tweetId":                tweetID,
+		"count":                  maxProfilesNbr,
+		"includePromotedContent": false,
+	}
+	features := map[string]interface{}{
+		"rweb_tipjar_consumption_enabled":                                         true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"communities_web_enable_tweet_community_results_fetch":                    true,
+		"c9s_tweet_anatomy_moderator_badge_enabled":                               true,
+		"articles_preview_enabled":                                                true,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"responsive_web_twitter_article_tweet_consumption_enabled":                true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"creator_subscriptions_quote_tweet_preview_enabled":                       false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": true,
+		"rweb_video_timestamps_enabled":                                           true,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
+
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
+
+	var timeline retweetersTimeline
+	err = s.RequestAPI(req, &timeline)
+	if err != nil {
+		return nil, "", err
+	}
+
+	profiles, nextCursor := parseTimelineUsers(timeline.Data.RetweetersTimeline.Timeline.Instructions)
+	return profiles, nextCursor, nil
+}
@@ -0,0 +1,126 @@
+package twitterscraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// usersTimelineResponse encodes users as timeline instructions followed by a
+// bottom cursor.
+func usersTimelineResponse(cursor string, usernames ...string) []interface{} {
+	var entries []interface{}
+	for _, username := range usernames {
+		entries = append(entries, map[string]interface{}{
+			"entryId": "user-" + username,
+			"content": map[string]interface{}{"itemContent": map[string]interface{}{
+				"user_results": map[string]interface{}{"result": userJSON("id-"+username, username)},
+			}},
+		})
+	}
+	entries = append(entries, map[string]interface{}{
+		"entryId": "cursor-bottom-" + cursor,
+		"content": map[string]interface{}{"cursorType": "Bottom", "value": cursor},
+	})
+	return []interface{}{map[string]interface{}{"type": "TimelineAddEntries", "entries": entries}}
+}
+
+func requestVariables(t *testing.T, req *http.Request) map[string]interface{} {
+	var variables map[string]interface{}
+	if err := json.Unmarshal([]byte(req.URL.Query().Get("variables")), &variables); err != nil {
+		t.Fatal(err)
+	}
+	return variables
+}
+
+func TestGetRetweeters(t *testing.T) {
+	pages := map[string][]interface{}{
+		"":   usersTimelineResponse("c1", "alice", "bob"),
+		"c1": usersTimelineResponse("c2", "carol"),
+		"c2": usersTimelineResponse("c3"),
+	}
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		variables := requestVariables(t, req)
+		if variables["tweetId"] != "42" {
+			t.Errorf("Expected tweetId 42, got %v", variables["tweetId"])
+		}
+		cursor, _ := variables["cursor"].(string)
+		return map[string]interface{}{"data": map[string]interface{}{"retweeters_timeline": map[string]interface{}{
+			"timeline": map[string]interface{}{"instructions": pages[cursor]},
+		}}}
+	})
+
+	var usernames, ids []string
+	for result := range scraper.GetRetweeters(context.Background(), "42", 10) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		usernames = append(usernames, result.Username)
+		ids = append(ids, result.UserID)
+	}
+	if diff := cmp.Diff([]string{"alice", "bob", "carol"}, usernames); diff != "" {
+		t.Error("Retweeters do not match", diff)
+	}
+	if diff := cmp.Diff([]string{"id-alice", "id-bob", "id-carol"}, ids); diff != "" {
+		t.Error("Retweeter IDs do not match", diff)
+	}
+}
+
+// searchTweetsResponse encodes tweets as a SearchTimeline page followed by a
+// bottom cursor.
+func searchTweetsResponse(cursor string, ids ...string) interface{} {
+	var entries []interface{}
+	for _, id := range ids {
+		entries = append(entries, map[string]interface{}{
+			"entryId": "tweet-" + id,
+			"content": map[string]interface{}{"itemContent": map[string]interface{}{
+				"tweetDisplayType": "Tweet",
+				"tweet_results":    map[string]interface{}{"result": tweetResultJSON(id, "alice")},
+			}},
+		})
+	}
+	entries = append(entries, map[string]interface{}{
+		"entryId": "cursor-bottom-" + cursor,
+		"content": map[string]interface{}{"cursorType": "Bottom", "value": cursor},
+	})
+	return map[string]interface{}{"data": map[string]interface{}{"search_by_raw_query": map[string]interface{}{
+		"search_timeline": map[string]interface{}{"timeline": map[string]interface{}{"instructions": []interface{}{
+			map[string]interface{}{"type": "TimelineAddEntries", "entries": entries},
+		}}},
+	}}}
+}
+
+func TestGetQuoteTweets(t *testing.T) {
+	pages := map[string]interface{}{
+		"":   searchTweetsResponse("c1", "1", "2"),
+		"c1": searchTweetsResponse("c2", "3"),
+		"c2": searchTweetsResponse("c3"),
+	}
+	var cursors []string
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		variables := requestVariables(t, req)
+		if query := variables["rawQuery"]; query != "quoted_tweet_id:42" {
+			t.Errorf("Expected quoted_tweet_id:42 search, got %v", query)
+		}
+		cursor, _ := variables["cursor"].(string)
+		cursors = append(cursors, cursor)
+		return pages[cursor]
+	})
+
+	var ids []string
+	for result := range scraper.GetQuoteTweets(context.Background(), "42", 10) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		ids = append(ids, result.ID)
+	}
+	if diff := cmp.Diff([]string{"1", "2", "3"}, ids); diff != "" {
+		t.Error("Quote tweets do not match", diff)
+	}
+	if diff := cmp.Diff([]string{"", "c1", "c2"}, cursors); diff != "" {
+		t.Error("Requested cursors do not match", diff)
+	}
+}
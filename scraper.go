@@ -24,12 +24,15 @@ type Scraper struct {
 	includeReplies bool
 	isLogged       bool
 	isOpenAccount  bool
-	oAuthToken     string
-	oAuthSecret    string
-	proxy          string
-	searchMode     SearchMode
-	wg             sync.WaitGroup
-	userAgent      string
+	// mu guards the tokens and the delay shared by concurrent requests.
+	mu            sync.Mutex
+	nextRequestAt time.Time
+	oAuthToken    string
+	oAuthSecret   string
+	proxy         string
+	searchMode    SearchMode
+	skipPromoted  bool
+	userAgent     string
 }
 
 // SearchMode type
@@ -48,6 +51,10 @@ const (
 	SearchUsers
 )
 
+// ErrLoginRequired is returned by methods that need a session logged in
+// with a user account. Guest and open account sessions can't use them.
+var ErrLoginRequired = errors.New("login required")
+
 // default http client timeout
 const DefaultClientTimeout = 10 * time.Second
 
@@ -62,6 +69,16 @@ func (s *Scraper) getHTTPClient() *http.Client {
 	return s.client
 }
 
+// HTTPClient returns an HTTP client sharing the transport, proxy and cookies
+// of the scraper. Use it to fetch media through the same proxy. Unlike the
+// client of the scraper, it has no overall timeout, which would cut the
+// download of long videos: bound requests with a context instead.
+func (s *Scraper) HTTPClient() *http.Client {
+	client := *s.getHTTPClient()
+	client.Timeout = 0
+	return &client
+}
+
 // New creates a Scraper object
 func New() *Scraper {
 	jar, _ := cookiejar.New(nil)
@@ -76,13 +93,26 @@ func New() *Scraper {
 	return scraper
 }
 
+// requireUserSession returns ErrLoginRequired unless the scraper is logged in
+// with a user account.
+func (s *Scraper) requireUserSession() error {
+	if !s.isLogged || s.isOpenAccount {
+		return ErrLoginRequired
+	}
+	return nil
+}
+
 func (s *Scraper) setBearerToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.bearerToken = token
 	s.guestToken = ""
 }
 
 // IsGuestToken check if guest token not empty
 func (s *Scraper) IsGuestToken() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.guestToken != ""
 }
 
@@ -98,18 +128,33 @@ func (s *Scraper) WithDelay(seconds int64) *Scraper {
 	return s
 }
 
-// WithReplies enable/disable load timeline with tweet replies
+// WithReplies enable/disable load timeline with tweet replies.
+// Only the legacy timeline used by open accounts honors it; use
+// GetTweetsAndReplies with other sessions.
 func (s *Scraper) WithReplies(b bool) *Scraper {
 	s.includeReplies = b
 	return s
 }
 
+// WithSkipPromoted enable/disable skipping promoted tweets in the home timeline
+func (s *Scraper) WithSkipPromoted(b bool) *Scraper {
+	s.skipPromoted = b
+	return s
+}
+
 // client timeout
 func (s *Scraper) WithClientTimeout(timeout time.Duration) *Scraper {
 	s.client.Timeout = timeout
 	return s
 }
 
+// WithTransport sets the transport the scraper sends its requests with,
+// for instance to instrument them. SetProxy replaces it.
+func (s *Scraper) WithTransport(transport http.RoundTripper) *Scraper {
+	s.client.Transport = transport
+	return s
+}
+
 // SetProxy
 // set http proxy in the format `http://HOST:PORT`
 // set socket proxy in the format `socks5://HOST:PORT`
@@ -4,7 +4,6 @@ import (
 	"context"
 	"net/http"
 	"net/url"
-	"strconv"
 )
 
 const searchURL = "https://twitter.com/i/api/graphql/MJpyQGqgklrVl_0X9gNy3A/SearchTimeline"
@@ -36,10 +35,7 @@ func (timeline *searchTimeline) parseTweets() ([]*Tweet, string) {
 			}
 			for _, entry := range instruction.Entries {
 				if entry.Content.ItemContent.TweetDisplayType == "Tweet" {
-					if tweet := parseLegacyTweet(&entry.Content.ItemContent.TweetResults.Result.Core.UserResults.Result.Legacy, &entry.Content.ItemContent.TweetResults.Result.Legacy); tweet != nil {
-						if tweet.Views == 0 && entry.Content.ItemContent.TweetResults.Result.Views.Count != "" {
-							tweet.Views, _ = strconv.Atoi(entry.Content.ItemContent.TweetResults.Result.Views.Count)
-						}
+					if tweet := entry.Content.ItemContent.TweetResults.Result.parse(); tweet != nil {
 						tweets = append(tweets, tweet)
 					}
 				} else if entry.Content.CursorType == "Bottom" {
@@ -62,10 +58,7 @@ func (timeline *searchTimeline) parseUsers() ([]*Profile, string) {
 			}
 			for _, entry := range instruction.Entries {
 				if entry.Content.ItemContent.UserDisplayType == "User" {
-					if profile := parseProfile(entry.Content.ItemContent.UserResults.Result.Legacy); profile.Name != "" {
-						if profile.UserID == "" {
-							profile.UserID = entry.Content.ItemContent.UserResults.Result.RestID
-						}
+					if profile := entry.Content.ItemContent.UserResults.Result.profile(); profile.Name != "" {
 						profiles = append(profiles, &profile)
 					}
 				} else if entry.Content.CursorType == "Bottom" {
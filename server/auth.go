@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// quota is a fixed-window request counter for a single API key.
+type quota struct {
+	windowStart time.Time
+	count       int
+}
+
+type limiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	quotas map[string]*quota
+}
+
+func newLimiter(limit int, window time.Duration) *limiter {
+	return &limiter{
+		limit:  limit,
+		window: window,
+		quotas: make(map[string]*quota),
+	}
+}
+
+// allow reports whether the client may issue another request, and the time
+// at which its current window resets.
+func (l *limiter) allow(key string, now time.Time) (bool, time.Time) {
+	if l.limit <= 0 {
+		return true, time.Time{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	q, ok := l.quotas[key]
+	if !ok || now.Sub(q.windowStart) >= l.window {
+		q = &quota{windowStart: now}
+		l.quotas[key] = q
+	}
+	reset := q.windowStart.Add(l.window)
+	if q.count >= l.limit {
+		return false, reset
+	}
+	q.count++
+	return true, reset
+}
+
+// apiKey extracts the client key from the X-API-Key header or a bearer
+// Authorization header.
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	auth := r.Header.Get("Authorization")
+	if strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+)
+
+// loginCheckInterval bounds how often the pool asks Twitter whether an
+// account session is still valid.
+const loginCheckInterval = time.Minute
+
+// Pool of logged in scrapers used to serve requests in round-robin order.
+// A Scraper is not safe for concurrent use, so the pool checks each one out
+// to a single caller at a time.
+type Pool struct {
+	mu       sync.Mutex
+	accounts []*account
+	// idle holds the accounts not checked out, least recently used first.
+	idle chan *account
+}
+
+type account struct {
+	scraper   *twitterscraper.Scraper
+	loggedIn  bool
+	checkedAt time.Time
+}
+
+// AccountState of a scraper in the pool, as reported by /healthz.
+type AccountState struct {
+	Index     int       `json:"index"`
+	LoggedIn  bool      `json:"logged_in"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// NewPool creates a pool from already configured scrapers.
+func NewPool(scrapers ...*twitterscraper.Scraper) *Pool {
+	pool := &Pool{idle: make(chan *account, len(scrapers))}
+	for _, scraper := range scrapers {
+		acc := &account{scraper: scraper}
+		pool.accounts = append(pool.accounts, acc)
+		pool.idle <- acc
+	}
+	return pool
+}
+
+// Len returns the number of scrapers in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.accounts)
+}
+
+// Acquire waits for an idle scraper and checks it out until release is
+// called. Scrapers are handed out in round-robin order.
+func (p *Pool) Acquire(ctx context.Context) (scraper *twitterscraper.Scraper, release func(), err error) {
+	if p.Len() == 0 {
+		return nil, nil, fmt.Errorf("server: scraper pool is empty")
+	}
+	select {
+	case acc := <-p.idle:
+		var once sync.Once
+		return acc.scraper, func() { once.Do(func() { p.idle <- acc }) }, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// State reports the login state of every scraper in the pool. The state is
+// verified against Twitter at most once per loginCheckInterval, and only for
+// idle scrapers, which are checked out during the check. Scrapers serving a
+// request keep their last known state.
+func (p *Pool) State() []AccountState {
+	var idle []*account
+collect:
+	for len(idle) < cap(p.idle) {
+		select {
+		case acc := <-p.idle:
+			idle = append(idle, acc)
+		default:
+			break collect
+		}
+	}
+
+	var due []*account
+	p.mu.Lock()
+	for _, acc := range idle {
+		if time.Since(acc.checkedAt) > loginCheckInterval {
+			due = append(due, acc)
+		} else {
+			p.idle <- acc
+		}
+	}
+	p.mu.Unlock()
+
+	for _, acc := range due {
+		loggedIn := acc.scraper.VerifyLogin()
+		p.mu.Lock()
+		acc.loggedIn = loggedIn
+		acc.checkedAt = time.Now()
+		p.mu.Unlock()
+		p.idle <- acc
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	states := make([]AccountState, 0, len(p.accounts))
+	for i, acc := range p.accounts {
+		states = append(states, AccountState{
+			Index:     i,
+			LoggedIn:  acc.loggedIn,
+			CheckedAt: acc.checkedAt,
+		})
+	}
+	return states
+}
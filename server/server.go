@@ -0,0 +1,331 @@
+// Package server exposes a Scraper pool as an HTTP/JSON REST API.
+//
+// Endpoints (all but /healthz require an API key passed in the X-API-Key
+// header or as a bearer token):
+//
+//	GET /healthz
+//	GET /v1/profiles/{username}
+//	GET /v1/tweets/{id}
+//	GET /v1/users/{username}/tweets?max=N
+//	GET /v1/users/{username}/followers?max=N&cursor=C
+//	GET /v1/search/tweets?q=QUERY&max=N
+//	GET /v1/search/profiles?q=QUERY&max=N
+//	GET /v1/trends
+//
+// Streaming endpoints write newline-delimited JSON, or Server-Sent Events
+// when the client sends "Accept: text/event-stream".
+//
+// The followers endpoint returns profiles, encoded like /v1/profiles, under
+// "followers", and the cursor of the next page under "next_cursor".
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxResults is the number of items returned by streaming
+	// endpoints when the request does not set max.
+	DefaultMaxResults = 50
+	// DefaultMaxResultsLimit caps the max parameter of streaming endpoints.
+	DefaultMaxResultsLimit = 1000
+)
+
+// Config of the HTTP server.
+type Config struct {
+	// APIKeys allowed to call the API. Must not be empty.
+	APIKeys []string
+	// Quota is the number of requests each API key may issue per
+	// QuotaWindow. Zero disables quotas.
+	Quota int
+	// QuotaWindow defaults to one minute.
+	QuotaWindow time.Duration
+	// MaxResultsLimit caps the max parameter, defaults to DefaultMaxResultsLimit.
+	MaxResultsLimit int
+}
+
+// Server is an http.Handler serving the scraper API.
+type Server struct {
+	config  Config
+	pool    *Pool
+	limiter *limiter
+	mux     *http.ServeMux
+}
+
+// New creates a Server using scrapers from pool.
+func New(pool *Pool, config Config) (*Server, error) {
+	if pool == nil || pool.Len() == 0 {
+		return nil, fmt.Errorf("server: scraper pool is empty")
+	}
+	if len(config.APIKeys) == 0 {
+		return nil, fmt.Errorf("server: at least one API key is required")
+	}
+	if config.QuotaWindow <= 0 {
+		config.QuotaWindow = time.Minute
+	}
+	if config.MaxResultsLimit <= 0 {
+		config.MaxResultsLimit = DefaultMaxResultsLimit
+	}
+
+	s := &Server{
+		config:  config,
+		pool:    pool,
+		limiter: newLimiter(config.Quota, config.QuotaWindow),
+		mux:     http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.Handle("/v1/profiles/", s.authorized(s.handleProfile))
+	s.mux.Handle("/v1/tweets/", s.authorized(s.handleTweet))
+	s.mux.Handle("/v1/users/", s.authorized(s.handleUser))
+	s.mux.Handle("/v1/search/tweets", s.authorized(s.handleSearchTweets))
+	s.mux.Handle("/v1/search/profiles", s.authorized(s.handleSearchProfiles))
+	s.mux.Handle("/v1/trends", s.authorized(s.handleTrends))
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		key := apiKey(r)
+		if !s.validKey(key) {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid API key"))
+			return
+		}
+		if ok, reset := s.limiter.allow(key, time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+			writeError(w, http.StatusTooManyRequests, fmt.Errorf("quota exceeded"))
+			return
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, k := range s.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	accounts := s.pool.State()
+	loggedIn := 0
+	for _, acc := range accounts {
+		if acc.LoggedIn {
+			loggedIn++
+		}
+	}
+	status := http.StatusOK
+	if loggedIn == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"ok":        loggedIn > 0,
+		"logged_in": loggedIn,
+		"accounts":  accounts,
+	})
+}
+
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimPrefix(r.URL.Path, "/v1/profiles/")
+	if username == "" || strings.Contains(username, "/") {
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found"))
+		return
+	}
+	s.respond(w, r, func(scraper *twitterscraper.Scraper) (interface{}, error) {
+		return scraper.GetProfile(username)
+	})
+}
+
+func (s *Server) handleTweet(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v1/tweets/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found"))
+		return
+	}
+	s.respond(w, r, func(scraper *twitterscraper.Scraper) (interface{}, error) {
+		return scraper.GetTweet(id)
+	})
+}
+
+// handleUser serves /v1/users/{username}/tweets and /v1/users/{username}/followers.
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/users/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found"))
+		return
+	}
+	username := parts[0]
+	maxNbr, err := s.maxParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch parts[1] {
+	case "tweets":
+		scraper, release, ok := s.acquire(w, r)
+		if !ok {
+			return
+		}
+		streamTweets(w, r, scraper.GetTweets(r.Context(), username, maxNbr), release)
+	case "followers":
+		cursor := r.URL.Query().Get("cursor")
+		s.respond(w, r, func(scraper *twitterscraper.Scraper) (interface{}, error) {
+			followers, next, err := scraper.FetchFollowerProfiles(username, maxNbr, cursor)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"followers":   followers,
+				"next_cursor": next,
+			}, nil
+		})
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found"))
+	}
+}
+
+func (s *Server) handleSearchTweets(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("missing q parameter"))
+		return
+	}
+	maxNbr, err := s.maxParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	scraper, release, ok := s.acquire(w, r)
+	if !ok {
+		return
+	}
+	streamTweets(w, r, scraper.SearchTweets(r.Context(), query, maxNbr), release)
+}
+
+func (s *Server) handleSearchProfiles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("missing q parameter"))
+		return
+	}
+	maxNbr, err := s.maxParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	scraper, release, ok := s.acquire(w, r)
+	if !ok {
+		return
+	}
+	streamProfiles(w, r, scraper.SearchProfiles(r.Context(), query, maxNbr), release)
+}
+
+func (s *Server) handleTrends(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, r, func(scraper *twitterscraper.Scraper) (interface{}, error) {
+		return scraper.GetTrends()
+	})
+}
+
+func (s *Server) maxParam(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("max")
+	if raw == "" {
+		return DefaultMaxResults, nil
+	}
+	maxNbr, err := strconv.Atoi(raw)
+	if err != nil || maxNbr <= 0 {
+		return 0, fmt.Errorf("invalid max parameter %q", raw)
+	}
+	if maxNbr > s.config.MaxResultsLimit {
+		maxNbr = s.config.MaxResultsLimit
+	}
+	return maxNbr, nil
+}
+
+// acquire checks a scraper out of the pool for the request, or writes an
+// error when the client goes away first.
+func (s *Server) acquire(w http.ResponseWriter, r *http.Request) (*twitterscraper.Scraper, func(), bool) {
+	scraper, release, err := s.pool.Acquire(r.Context())
+	if err != nil {
+		if isCancelled(r.Context(), err) {
+			logrus.WithError(err).WithField("path", r.URL.Path).Debug("Request cancelled by client")
+		} else {
+			writeError(w, http.StatusServiceUnavailable, err)
+		}
+		return nil, nil, false
+	}
+	return scraper, release, true
+}
+
+// respond runs fn with a scraper from the pool and writes its result as
+// JSON. The scraper calls behind fn do not accept a context, so fn is left
+// to finish in the background when the client goes away, and the scraper
+// only returns to the pool once fn is done.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, fn func(scraper *twitterscraper.Scraper) (interface{}, error)) {
+	scraper, release, ok := s.acquire(w, r)
+	if !ok {
+		return
+	}
+	type reply struct {
+		value interface{}
+		err   error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		defer release()
+		value, err := fn(scraper)
+		done <- reply{value, err}
+	}()
+
+	select {
+	case <-r.Context().Done():
+		logrus.WithError(r.Context().Err()).WithField("path", r.URL.Path).Debug("Request cancelled by client")
+	case res := <-done:
+		if res.err != nil {
+			writeError(w, http.StatusBadGateway, res.err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res.value)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logrus.WithError(err).Error("Failed to encode response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// isCancelled reports whether err was caused by the client going away.
+func isCancelled(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && err == ctx.Err()
+}
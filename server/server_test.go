@@ -0,0 +1,196 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/masa-finance/masa-twitter-scraper/server"
+)
+
+func newTestServer(t *testing.T, config server.Config) *server.Server {
+	srv, err := server.New(server.NewPool(twitterscraper.New()), config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return srv
+}
+
+func doRequest(srv http.Handler, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/v1/search/tweets", nil)
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerAPIKey(t *testing.T) {
+	srv := newTestServer(t, server.Config{APIKeys: []string{"secret"}})
+
+	if rec := doRequest(srv, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without key, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if rec := doRequest(srv, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d with wrong key, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	// Missing q parameter is rejected after authentication.
+	if rec := doRequest(srv, "secret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d with valid key, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestServerQuota(t *testing.T) {
+	srv := newTestServer(t, server.Config{
+		APIKeys:     []string{"a", "b"},
+		Quota:       2,
+		QuotaWindow: time.Hour,
+	})
+
+	for i := 0; i < 2; i++ {
+		if rec := doRequest(srv, "a"); rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("Request %d rejected before quota was reached", i)
+		}
+	}
+	rec := doRequest(srv, "a")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d after quota, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header is set")
+	}
+	if rec := doRequest(srv, "b"); rec.Code == http.StatusTooManyRequests {
+		t.Error("Expected quota to be tracked per API key")
+	}
+}
+
+func TestServerConfig(t *testing.T) {
+	if _, err := server.New(server.NewPool(), server.Config{APIKeys: []string{"a"}}); err == nil {
+		t.Error("Expected error for empty pool")
+	}
+	if _, err := server.New(server.NewPool(twitterscraper.New()), server.Config{}); err == nil {
+		t.Error("Expected error without API keys")
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// blockingScraper returns a scraper whose requests wait for unblock, and a
+// count of the requests sent.
+func blockingScraper(unblock <-chan struct{}) (*twitterscraper.Scraper, *int32) {
+	var requests int32
+	scraper := twitterscraper.New()
+	scraper.WithTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		<-unblock
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"id_str": "1"}`)),
+			Request:    req,
+		}, nil
+	}))
+	return scraper, &requests
+}
+
+func TestPoolAcquireIsExclusive(t *testing.T) {
+	a, b := twitterscraper.New(), twitterscraper.New()
+	pool := server.NewPool(a, b)
+
+	first, releaseFirst, err := pool.Acquire(context.Background())
+	if err != nil || first != a {
+		t.Fatalf("Expected the first scraper, got %p %v", first, err)
+	}
+	second, releaseSecond, err := pool.Acquire(context.Background())
+	if err != nil || second != b {
+		t.Fatalf("Expected the second scraper, got %p %v", second, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := pool.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected Acquire to wait while every scraper is checked out, got %v", err)
+	}
+
+	releaseFirst()
+	releaseFirst()
+	if s, release, err := pool.Acquire(context.Background()); err != nil || s != a {
+		t.Fatalf("Expected the released scraper, got %p %v", s, err)
+	} else {
+		release()
+	}
+	releaseSecond()
+}
+
+func TestPoolStateSkipsBusyScrapers(t *testing.T) {
+	unblock := make(chan struct{})
+	close(unblock)
+	scraper, requests := blockingScraper(unblock)
+	pool := server.NewPool(scraper)
+
+	_, release, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := pool.State()
+	if len(state) != 1 || state[0].LoggedIn || !state[0].CheckedAt.IsZero() || atomic.LoadInt32(requests) != 0 {
+		t.Errorf("Expected the busy scraper left unchecked, got %+v", state)
+	}
+
+	release()
+	state = pool.State()
+	if len(state) != 1 || !state[0].LoggedIn || state[0].CheckedAt.IsZero() {
+		t.Errorf("Unexpected state %+v", state)
+	}
+	if scraper.IsGuestToken() {
+		t.Error("Expected the scraper left unchanged by the check")
+	}
+}
+
+func TestServerKeepsScraperUntilCancelledCallEnds(t *testing.T) {
+	unblock := make(chan struct{})
+	scraper, requests := blockingScraper(unblock)
+	pool := server.NewPool(scraper)
+	srv, err := server.New(pool, server.Config{APIKeys: []string{"key"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/v1/trends", nil).WithContext(ctx)
+	req.Header.Set("X-API-Key", "key")
+	served := make(chan struct{})
+	go func() {
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+		close(served)
+	}()
+	for atomic.LoadInt32(requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-served
+
+	wait, cancelWait := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelWait()
+	if _, _, err := pool.Acquire(wait); err == nil {
+		t.Fatal("Expected the scraper checked out while the abandoned call runs")
+	}
+
+	close(unblock)
+	s, release, err := pool.Acquire(context.Background())
+	if err != nil || s != scraper {
+		t.Fatalf("Expected the scraper back in the pool, got %p %v", s, err)
+	}
+	release()
+}
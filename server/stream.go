@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/sirupsen/logrus"
+)
+
+// streamWriter writes items either as NDJSON or as Server-Sent Events.
+type streamWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	sse     bool
+}
+
+func newStreamWriter(w http.ResponseWriter, r *http.Request) *streamWriter {
+	sw := &streamWriter{
+		w:   w,
+		sse: strings.Contains(r.Header.Get("Accept"), "text/event-stream"),
+	}
+	sw.flusher, _ = w.(http.Flusher)
+	if sw.sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.WriteHeader(http.StatusOK)
+	return sw
+}
+
+// write sends a single item. SSE events are named after event.
+func (sw *streamWriter) write(event string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if sw.sse {
+		_, err = fmt.Fprintf(sw.w, "event: %s\ndata: %s\n\n", event, data)
+	} else {
+		_, err = fmt.Fprintf(sw.w, "%s\n", data)
+	}
+	if err != nil {
+		return err
+	}
+	if sw.flusher != nil {
+		sw.flusher.Flush()
+	}
+	return nil
+}
+
+// writeError reports an error in the middle of a stream, after the status
+// code has already been sent.
+func (sw *streamWriter) writeError(err error) {
+	if werr := sw.write("error", map[string]string{"error": err.Error()}); werr != nil {
+		logrus.WithError(werr).Debug("Failed to write stream error")
+	}
+}
+
+// streamTweets writes tweets to the client. release is called once the
+// channel is closed, when the scraper producing it is done.
+func streamTweets(w http.ResponseWriter, r *http.Request, tweets <-chan *twitterscraper.TweetResult, release func()) {
+	sw := newStreamWriter(w, r)
+	for tweet := range tweets {
+		if tweet.Error != nil {
+			if !isCancelled(r.Context(), tweet.Error) {
+				sw.writeError(tweet.Error)
+			}
+			continue
+		}
+		if err := sw.write("tweet", tweet.Tweet); err != nil {
+			logrus.WithError(err).Debug("Failed to write tweet to stream")
+			drainTweets(tweets, release)
+			return
+		}
+	}
+	release()
+}
+
+// streamProfiles is like streamTweets for profiles.
+func streamProfiles(w http.ResponseWriter, r *http.Request, profiles <-chan *twitterscraper.ProfileResult, release func()) {
+	sw := newStreamWriter(w, r)
+	for profile := range profiles {
+		if profile.Error != nil {
+			if !isCancelled(r.Context(), profile.Error) {
+				sw.writeError(profile.Error)
+			}
+			continue
+		}
+		if err := sw.write("profile", profile.Profile); err != nil {
+			logrus.WithError(err).Debug("Failed to write profile to stream")
+			drainProfiles(profiles, release)
+			return
+		}
+	}
+	release()
+}
+
+// drainTweets consumes the rest of a channel so the producing goroutine can
+// observe the cancelled context and exit, then calls release.
+func drainTweets(tweets <-chan *twitterscraper.TweetResult, release func()) {
+	go func() {
+		for range tweets {
+		}
+		release()
+	}()
+}
+
+func drainProfiles(profiles <-chan *twitterscraper.ProfileResult, release func()) {
+	go func() {
+		for range profiles {
+		}
+		release()
+	}()
+}
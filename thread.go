@@ -0,0 +1,116 @@
+package twitterscraper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetThread returns the complete self-thread containing a tweet: the chain of
+// tweets where the author replies to themselves, oldest first. The tweet can
+// be anywhere in the thread. A tweet that is not part of a self-thread is
+// returned alone.
+func (s *Scraper) GetThread(tweetID string) ([]*Tweet, error) {
+	var order []*Tweet
+	loaded := make(map[string]*Tweet)
+	fetched := make(map[string]bool)
+	load := func(id string) error {
+		if fetched[id] {
+			return nil
+		}
+		fetched[id] = true
+		tweets, _, err := s.fetchConversationPage(id, "")
+		if err != nil {
+			return err
+		}
+		for _, tweet := range tweets {
+			if _, ok := loaded[tweet.ID]; !ok {
+				loaded[tweet.ID] = tweet
+				order = append(order, tweet)
+			}
+		}
+		return nil
+	}
+
+	if err := load(tweetID); err != nil {
+		return nil, err
+	}
+	focal := loaded[tweetID]
+	if focal == nil {
+		return nil, fmt.Errorf("tweet with ID %s not found", tweetID)
+	}
+
+	// Walk up to the first tweet of the thread. TweetDetail returns the
+	// ancestors of the focal tweet, so the parent is usually loaded already.
+	head := focal
+	for head.InReplyToStatusID != "" {
+		parent := loaded[head.InReplyToStatusID]
+		if parent == nil {
+			if err := load(head.InReplyToStatusID); err != nil {
+				return nil, err
+			}
+			parent = loaded[head.InReplyToStatusID]
+		}
+		if parent == nil || !sameAuthor(parent, focal) {
+			break
+		}
+		head = parent
+	}
+
+	// Walk down. The author's continuation is ranked first among the replies
+	// of each tweet; load the replies of a tweet only when it is missing.
+	thread := []*Tweet{head}
+	inThread := map[string]bool{head.ID: true}
+	for last := head; ; {
+		next := findContinuation(order, last)
+		if next == nil && !fetched[last.ID] {
+			if err := load(last.ID); err != nil {
+				return nil, err
+			}
+			next = findContinuation(order, last)
+		}
+		if next == nil || inThread[next.ID] {
+			break
+		}
+		inThread[next.ID] = true
+		thread = append(thread, next)
+		last = next
+	}
+
+	if len(thread) > 1 {
+		for i, tweet := range thread {
+			tweet.IsSelfThread = true
+			if i > 0 {
+				tweet.InReplyToStatus = thread[i-1]
+			}
+		}
+		head.Thread = thread[1:]
+	}
+	return thread, nil
+}
+
+// JoinThread renders a thread as a single document: the text of every tweet,
+// in order, separated by blank lines.
+func JoinThread(thread []*Tweet) string {
+	texts := make([]string, 0, len(thread))
+	for _, tweet := range thread {
+		texts = append(texts, strings.TrimSpace(tweet.Text))
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// findContinuation returns the first reply to parent by the same author.
+func findContinuation(tweets []*Tweet, parent *Tweet) *Tweet {
+	for _, tweet := range tweets {
+		if tweet.InReplyToStatusID == parent.ID && sameAuthor(tweet, parent) {
+			return tweet
+		}
+	}
+	return nil
+}
+
+func sameAuthor(a, b *Tweet) bool {
+	if a.UserID != "" && b.UserID != "" {
+		return a.UserID == b.UserID
+	}
+	return strings.EqualFold(a.Username, b.Username)
+}
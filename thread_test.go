@@ -0,0 +1,93 @@
+package twitterscraper
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeTweet struct {
+	id, user, replyTo, text, noteText string
+}
+
+// tweetDetailResponse encodes tweets as a TweetDetail response.
+func tweetDetailResponse(tweets ...fakeTweet) interface{} {
+	var entries []interface{}
+	for _, tweet := range tweets {
+		result := tweetResultJSON(tweet.id, tweet.user)
+		legacy := result["legacy"].(map[string]interface{})
+		legacy["conversation_id_str"] = "300"
+		legacy["in_reply_to_status_id_str"] = tweet.replyTo
+		legacy["full_text"] = tweet.text
+		if tweet.noteText != "" {
+			result["note_tweet"] = map[string]interface{}{"note_tweet_results": map[string]interface{}{
+				"result": map[string]interface{}{"text": tweet.noteText},
+			}}
+		}
+		entries = append(entries, map[string]interface{}{
+			"entryId": "tweet-" + tweet.id,
+			"content": map[string]interface{}{"itemContent": map[string]interface{}{
+				"tweet_results": map[string]interface{}{"result": result},
+			}},
+		})
+	}
+	return map[string]interface{}{"data": map[string]interface{}{
+		"threaded_conversation_with_injections_v2": map[string]interface{}{
+			"instructions": []interface{}{map[string]interface{}{"type": "TimelineAddEntries", "entries": entries}},
+		},
+	}}
+}
+
+func focalTweetID(t *testing.T, req *http.Request) string {
+	var variables struct {
+		FocalTweetID string `json:"focalTweetId"`
+	}
+	if err := json.Unmarshal([]byte(req.URL.Query().Get("variables")), &variables); err != nil {
+		t.Fatal(err)
+	}
+	return variables.FocalTweetID
+}
+
+func TestGetThread(t *testing.T) {
+	t300 := fakeTweet{id: "300", user: "alice", text: "1/ start"}
+	t301 := fakeTweet{id: "301", user: "alice", replyTo: "300", text: "2/ middle"}
+	t302 := fakeTweet{id: "302", user: "alice", replyTo: "301", text: "3/ more"}
+	t303 := fakeTweet{id: "303", user: "alice", replyTo: "302", text: "4/ trunc", noteText: "4/ end, long text"}
+	t310 := fakeTweet{id: "310", user: "bob", replyTo: "301", text: "nice"}
+	t311 := fakeTweet{id: "311", user: "bob", replyTo: "303", text: "thanks"}
+	pages := map[string]interface{}{
+		"301": tweetDetailResponse(t300, t301, t310, t302),
+		"302": tweetDetailResponse(t300, t301, t302, t303),
+		"303": tweetDetailResponse(t300, t301, t302, t303, t311),
+	}
+	var requested []string
+	scraper := newFakeScraper(t, func(req *http.Request) interface{} {
+		id := focalTweetID(t, req)
+		requested = append(requested, id)
+		return pages[id]
+	})
+
+	thread, err := scraper.GetThread("301")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, tweet := range thread {
+		ids = append(ids, tweet.ID)
+	}
+	if diff := cmp.Diff([]string{"300", "301", "302", "303"}, ids); diff != "" {
+		t.Error("Thread does not match", diff)
+	}
+	if diff := cmp.Diff([]string{"301", "302", "303"}, requested); diff != "" {
+		t.Error("Requested pages do not match", diff)
+	}
+	if len(thread[0].Thread) != 3 || !thread[3].IsSelfThread || thread[3].InReplyToStatus != thread[2] {
+		t.Error("Expected thread tweets to be linked")
+	}
+	expected := "1/ start\n\n2/ middle\n\n3/ more\n\n4/ end, long text"
+	if got := JoinThread(thread); got != expected {
+		t.Errorf("Expected joined thread %q, got %q", expected, got)
+	}
+}
@@ -3,20 +3,21 @@ package twitterscraper
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 )
 
 // legacy timeline JSON object
 type timelineV1 struct {
 	GlobalObjects struct {
-		Tweets map[string]legacyTweet `json:"tweets"`
-		Users  map[string]legacyUser  `json:"users"`
+		Tweets        map[string]legacyTweet        `json:"tweets"`
+		Users         map[string]legacyUser         `json:"users"`
+		Notifications map[string]legacyNotification `json:"notifications"`
 	} `json:"globalObjects"`
 	Timeline struct {
 		Instructions []struct {
 			AddEntries struct {
 				Entries []struct {
+					EntryID string `json:"entryId"`
 					Content struct {
 						Item struct {
 							Content struct {
@@ -26,6 +27,9 @@ type timelineV1 struct {
 								User struct {
 									ID string `json:"id"`
 								} `json:"user"`
+								Notification struct {
+									ID string `json:"id"`
+								} `json:"notification"`
 							} `json:"content"`
 						} `json:"item"`
 						Operation struct {
@@ -110,11 +114,15 @@ func (timeline *timelineV1) parseTweet(id string) *Tweet {
 			tw.Place = &tweet.Place
 		}
 
+		tw.Card, tw.Poll = tweet.Card.parse()
+		tweet.EditControl.apply(tw)
+
 		if tweet.QuotedStatusIDStr != "" {
 			tw.IsQuoted = true
 			tw.QuotedStatus = timeline.parseTweet(tweet.QuotedStatusIDStr)
 			tw.QuotedStatusID = tweet.QuotedStatusIDStr
 		}
+		parseTweetMetadata(tw, &tweet)
 		if tweet.InReplyToStatusIDStr != "" {
 			tw.IsReply = true
 			tw.InReplyToStatus = timeline.parseTweet(tweet.InReplyToStatusIDStr)
@@ -157,84 +165,14 @@ func (timeline *timelineV1) parseTweet(id string) *Tweet {
 			})
 		}
 
-		for _, media := range tweet.ExtendedEntities.Media {
-			if media.Type == "photo" {
-				photo := Photo{
-					ID:  media.IDStr,
-					URL: media.MediaURLHttps,
-				}
-
-				tw.Photos = append(tw.Photos, photo)
-			} else if media.Type == "video" {
-				video := Video{
-					ID:      media.IDStr,
-					Preview: media.MediaURLHttps,
-				}
-
-				maxBitrate := 0
-				for _, variant := range media.VideoInfo.Variants {
-					if variant.Bitrate > maxBitrate {
-						video.URL = strings.TrimSuffix(variant.URL, "?tag=10")
-						maxBitrate = variant.Bitrate
-					}
-				}
-
-				tw.Videos = append(tw.Videos, video)
-			}
-
-			if !tw.SensitiveContent {
-				sensitive := media.ExtSensitiveMediaWarning
-				tw.SensitiveContent = sensitive.AdultContent || sensitive.GraphicViolence || sensitive.Other
-			}
-		}
+		parseMedia(tw, tweet.ExtendedEntities.Media)
 
 		for _, url := range tweet.Entities.URLs {
 			tw.URLs = append(tw.URLs, url.ExpandedURL)
 		}
 
-		tw.HTML = tweet.FullText
-		tw.HTML = reHashtag.ReplaceAllStringFunc(tw.HTML, func(hashtag string) string {
-			return fmt.Sprintf(`<a href="https://twitter.com/hashtag/%s">%s</a>`,
-				strings.TrimPrefix(hashtag, "#"),
-				hashtag,
-			)
-		})
-		tw.HTML = reUsername.ReplaceAllStringFunc(tw.HTML, func(username string) string {
-			return fmt.Sprintf(`<a href="https://twitter.com/%s">%s</a>`,
-				strings.TrimPrefix(username, "@"),
-				username,
-			)
-		})
-		var foundedMedia []string
-		tw.HTML = reTwitterURL.ReplaceAllStringFunc(tw.HTML, func(tco string) string {
-			for _, entity := range tweet.Entities.URLs {
-				if tco == entity.URL {
-					return fmt.Sprintf(`<a href="%s">%s</a>`, entity.ExpandedURL, tco)
-				}
-			}
-			for _, entity := range tweet.ExtendedEntities.Media {
-				if tco == entity.URL {
-					foundedMedia = append(foundedMedia, entity.MediaURLHttps)
-					return fmt.Sprintf(`<br><a href="%s"><img src="%s"/></a>`, tco, entity.MediaURLHttps)
-				}
-			}
-			return tco
-		})
-		for _, photo := range tw.Photos {
-			url := photo.URL
-			if stringInSlice(url, foundedMedia) {
-				continue
-			}
-			tw.HTML += fmt.Sprintf(`<br><img src="%s"/>`, url)
-		}
-		for _, video := range tw.Videos {
-			url := video.Preview
-			if stringInSlice(url, foundedMedia) {
-				continue
-			}
-			tw.HTML += fmt.Sprintf(`<br><img src="%s"/>`, url)
-		}
-		tw.HTML = strings.Replace(tw.HTML, "\n", "<br>", -1)
+		tw.Entities = parseEntities(tw.Text, &tweet)
+		tw.HTML = tw.RenderHTML()
 		return tw
 	}
 	return nil
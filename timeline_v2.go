@@ -2,16 +2,15 @@ package twitterscraper
 
 import (
 	"strconv"
+	"strings"
 )
 
 type result struct {
 	Typename string `json:"__typename"`
+	RestID   string `json:"rest_id"`
 	Core     struct {
 		UserResults struct {
-			Result struct {
-				IsBlueVerified bool       `json:"is_blue_verified"`
-				Legacy         legacyUser `json:"legacy"`
-			} `json:"result"`
+			Result userResult `json:"result"`
 		} `json:"user_results"`
 	} `json:"core"`
 	Views struct {
@@ -27,7 +26,22 @@ type result struct {
 	QuotedStatusResult struct {
 		Result *result `json:"result"`
 	} `json:"quoted_status_result"`
+	Card           graphQLCard    `json:"card"`
+	BirdwatchPivot birdwatchPivot `json:"birdwatch_pivot"`
+	EditControl    editControl    `json:"edit_control"`
+	// Source is set here by the GraphQL API, instead of in Legacy.
+	Source string      `json:"source"`
 	Legacy legacyTweet `json:"legacy"`
+	// Tweet is set instead of Core and Legacy in TweetWithVisibilityResults.
+	Tweet *result `json:"tweet"`
+	// Tombstone and Reason describe TweetTombstone and TweetUnavailable
+	// results.
+	Tombstone struct {
+		Text struct {
+			Text string `json:"text"`
+		} `json:"text"`
+	} `json:"tombstone"`
+	Reason string `json:"reason"`
 }
 
 func (result *result) parse() *Tweet {
@@ -44,39 +58,88 @@ func (result *result) parse() *Tweet {
 	if result.QuotedStatusResult.Result != nil {
 		tw.QuotedStatus = result.QuotedStatusResult.Result.parse()
 	}
+	card := result.Card.toLegacy()
+	tw.Card, tw.Poll = card.parse()
+	tw.CommunityNote = result.BirdwatchPivot.parse()
+	result.EditControl.apply(tw)
+	if tw.Source == "" {
+		tw.Source = parseSource(result.Source)
+	}
 	return tw
 }
 
+type itemContent struct {
+	ItemType         string `json:"itemType"`
+	TweetDisplayType string `json:"tweetDisplayType"`
+	TweetResults     struct {
+		Result result `json:"result"`
+	} `json:"tweet_results"`
+	UserDisplayType string `json:"userDisplayType"`
+	UserResults     struct {
+		Result userResult `json:"result"`
+	} `json:"user_results"`
+	List             listResult        `json:"list"`
+	PromotedMetadata *promotedMetadata `json:"promotedMetadata"`
+	CursorType       string            `json:"cursorType"`
+	Value            string            `json:"value"`
+}
+
+type promotedMetadata struct {
+	ImpressionID string `json:"impressionId"`
+}
+
+type moduleItem struct {
+	EntryID string `json:"entryId"`
+	Item    struct {
+		ItemContent itemContent `json:"itemContent"`
+	} `json:"item"`
+}
+
 type entry struct {
+	EntryID string `json:"entryId"`
 	Content struct {
-		CursorType string `json:"cursorType"`
-		Value      string `json:"value"`
-		Items      []struct {
-			Item struct {
-				ItemContent struct {
-					TweetDisplayType string `json:"tweetDisplayType"`
-					TweetResults     struct {
-						Result result `json:"result"`
-					} `json:"tweet_results"`
-				} `json:"itemContent"`
-			} `json:"item"`
-		} `json:"items"`
-		ItemContent struct {
-			TweetDisplayType string `json:"tweetDisplayType"`
-			TweetResults     struct {
-				Result result `json:"result"`
-			} `json:"tweet_results"`
-			UserDisplayType string `json:"userDisplayType"`
-			UserResults     struct {
-				Result struct {
-					RestID string     `json:"rest_id"`
-					Legacy legacyUser `json:"legacy"`
-				} `json:"result"`
-			} `json:"user_results"`
-		} `json:"itemContent"`
+		EntryType   string       `json:"entryType"`
+		CursorType  string       `json:"cursorType"`
+		Value       string       `json:"value"`
+		Items       []moduleItem `json:"items"`
+		ItemContent itemContent  `json:"itemContent"`
 	} `json:"content"`
 }
 
+// isPromoted reports whether the entry is an ad.
+func (entry *entry) isPromoted() bool {
+	return strings.HasPrefix(entry.EntryID, "promoted-") || entry.Content.ItemContent.PromotedMetadata != nil
+}
+
+// isPromoted reports whether the module item is an ad.
+func (item *moduleItem) isPromoted() bool {
+	return strings.Contains(item.EntryID, "promoted-") || item.Item.ItemContent.PromotedMetadata != nil
+}
+
+// withoutPromotedItems returns items with the promoted ones removed.
+func withoutPromotedItems(items []moduleItem) []moduleItem {
+	if len(items) == 0 {
+		return items
+	}
+	filtered := make([]moduleItem, 0, len(items))
+	for _, item := range items {
+		if !item.isPromoted() {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// instruction of a GraphQL timeline. TimelineAddToModule instructions append
+// ModuleItems to the module entry identified by ModuleEntryID.
+type instruction struct {
+	Type          string       `json:"type"`
+	Entries       []entry      `json:"entries"`
+	Entry         entry        `json:"entry"`
+	ModuleEntryID string       `json:"moduleEntryId"`
+	ModuleItems   []moduleItem `json:"moduleItems"`
+}
+
 // timeline v2 JSON object
 type timelineV2 struct {
 	Data struct {
@@ -84,18 +147,28 @@ type timelineV2 struct {
 			Result struct {
 				TimelineV2 struct {
 					Timeline struct {
-						Instructions []struct {
-							Entries []entry `json:"entries"`
-							Entry   entry   `json:"entry"`
-							Type    string  `json:"type"`
-						} `json:"instructions"`
+						Instructions []instruction `json:"instructions"`
 					} `json:"timeline"`
 				} `json:"timeline_v2"`
+				// Timeline is used instead of TimelineV2 by some operations,
+				// like UserHighlightsTweets.
+				Timeline struct {
+					Timeline struct {
+						Instructions []instruction `json:"instructions"`
+					} `json:"timeline"`
+				} `json:"timeline"`
 			} `json:"result"`
 		} `json:"user"`
 	} `json:"data"`
 }
 
+func (timeline *timelineV2) instructions() []instruction {
+	if instructions := timeline.Data.User.Result.TimelineV2.Timeline.Instructions; len(instructions) > 0 {
+		return instructions
+	}
+	return timeline.Data.User.Result.Timeline.Timeline.Instructions
+}
+
 func (timeline *timelineV2) parseTweets() ([]*Tweet, string) {
 	var cursor string
 	var tweets []*Tweet
@@ -115,41 +188,118 @@ func (timeline *timelineV2) parseTweets() ([]*Tweet, string) {
 	return tweets, cursor
 }
 
+// parseTimelineTweets returns the tweets of timeline instructions, including
+// the ones grouped in modules, and the cursor of the next page.
+func parseTimelineTweets(instructions []instruction) ([]*Tweet, string) {
+	var cursor string
+	var tweets []*Tweet
+	parseItem := func(content *itemContent) {
+		if content.CursorType == "Bottom" {
+			cursor = content.Value
+			return
+		}
+		if content.TweetResults.Result.Typename == "Tweet" {
+			if tweet := content.TweetResults.Result.parse(); tweet != nil {
+				tweets = append(tweets, tweet)
+			}
+		}
+	}
+	for _, instruction := range instructions {
+		if instruction.Entry.Content.CursorType == "Bottom" {
+			cursor = instruction.Entry.Content.Value
+		}
+		for _, entry := range instruction.Entries {
+			if entry.Content.CursorType == "Bottom" {
+				cursor = entry.Content.Value
+				continue
+			}
+			parseItem(&entry.Content.ItemContent)
+			for _, item := range entry.Content.Items {
+				parseItem(&item.Item.ItemContent)
+			}
+		}
+		for _, item := range instruction.ModuleItems {
+			parseItem(&item.Item.ItemContent)
+		}
+	}
+	return tweets, cursor
+}
+
+// withoutPromoted returns instructions with the promoted entries removed,
+// including the promoted items of modules.
+func withoutPromoted(instructions []instruction) []instruction {
+	filtered := make([]instruction, 0, len(instructions))
+	for _, instruction := range instructions {
+		entries := make([]entry, 0, len(instruction.Entries))
+		for _, entry := range instruction.Entries {
+			if !entry.isPromoted() {
+				entry.Content.Items = withoutPromotedItems(entry.Content.Items)
+				entries = append(entries, entry)
+			}
+		}
+		instruction.Entries = entries
+		instruction.ModuleItems = withoutPromotedItems(instruction.ModuleItems)
+		filtered = append(filtered, instruction)
+	}
+	return filtered
+}
+
+// parseTimelineUsers returns the profiles of timeline instructions and the
+// cursor of the next page.
+func parseTimelineUsers(instructions []instruction) ([]*Profile, string) {
+	var cursor string
+	var profiles []*Profile
+	for _, instruction := range instructions {
+		if instruction.Entry.Content.CursorType == "Bottom" {
+			cursor = instruction.Entry.Content.Value
+		}
+		for _, entry := range instruction.Entries {
+			if entry.Content.CursorType == "Bottom" {
+				cursor = entry.Content.Value
+				continue
+			}
+			user := entry.Content.ItemContent.UserResults.Result
+			if user.Legacy.ScreenName == "" {
+				continue
+			}
+			profile := user.profile()
+			profiles = append(profiles, &profile)
+		}
+	}
+	return profiles, cursor
+}
+
 type threadedConversation struct {
 	Data struct {
 		ThreadedConversationWithInjectionsV2 struct {
-			Instructions []struct {
-				Type    string  `json:"type"`
-				Entries []entry `json:"entries"`
-				Entry   entry   `json:"entry"`
-			} `json:"instructions"`
+			Instructions []instruction `json:"instructions"`
 		} `json:"threaded_conversation_with_injections_v2"`
 	} `json:"data"`
 }
 
 func (conversation *threadedConversation) parse() []*Tweet {
 	var tweets []*Tweet
+	appendTweet := func(content *itemContent) {
+		if content.TweetResults.Result.Typename != "Tweet" {
+			return
+		}
+		if tweet := content.TweetResults.Result.parse(); tweet != nil {
+			if content.TweetDisplayType == "SelfThread" {
+				tweet.IsSelfThread = true
+			}
+			tweets = append(tweets, tweet)
+		}
+	}
 	for _, instruction := range conversation.Data.ThreadedConversationWithInjectionsV2.Instructions {
 		for _, entry := range instruction.Entries {
-			if entry.Content.ItemContent.TweetResults.Result.Typename == "Tweet" {
-				if tweet := entry.Content.ItemContent.TweetResults.Result.parse(); tweet != nil {
-					if entry.Content.ItemContent.TweetDisplayType == "SelfThread" {
-						tweet.IsSelfThread = true
-					}
-					tweets = append(tweets, tweet)
-				}
-			}
+			appendTweet(&entry.Content.ItemContent)
 			for _, item := range entry.Content.Items {
-				if item.Item.ItemContent.TweetResults.Result.Typename == "Tweet" {
-					if tweet := item.Item.ItemContent.TweetResults.Result.parse(); tweet != nil {
-						if item.Item.ItemContent.TweetDisplayType == "SelfThread" {
-							tweet.IsSelfThread = true
-						}
-						tweets = append(tweets, tweet)
-					}
-				}
+				appendTweet(&item.Item.ItemContent)
 			}
 		}
+		for _, item := range instruction.ModuleItems {
+			appendTweet(&item.Item.ItemContent)
+		}
 	}
 	for _, tweet := range tweets {
 		if tweet.InReplyToStatusID != "" {
@@ -173,3 +323,37 @@ func (conversation *threadedConversation) parse() []*Tweet {
 	}
 	return tweets
 }
+
+// conversationCursors are the cursor types that load more replies: Bottom
+// pages through top level replies, ShowMore expands a reply thread, and
+// ShowMoreThreads(Prompt) reveals replies hidden as low quality.
+var conversationCursors = map[string]bool{
+	"Bottom":                true,
+	"ShowMore":              true,
+	"ShowMoreThreads":       true,
+	"ShowMoreThreadsPrompt": true,
+}
+
+// cursors returns the values of every cursor loading more replies, in
+// response order.
+func (conversation *threadedConversation) cursors() []string {
+	var cursors []string
+	appendCursor := func(cursorType, value string) {
+		if value != "" && conversationCursors[cursorType] {
+			cursors = append(cursors, value)
+		}
+	}
+	for _, instruction := range conversation.Data.ThreadedConversationWithInjectionsV2.Instructions {
+		for _, entry := range instruction.Entries {
+			appendCursor(entry.Content.CursorType, entry.Content.Value)
+			appendCursor(entry.Content.ItemContent.CursorType, entry.Content.ItemContent.Value)
+			for _, item := range entry.Content.Items {
+				appendCursor(item.Item.ItemContent.CursorType, item.Item.ItemContent.Value)
+			}
+		}
+		for _, item := range instruction.ModuleItems {
+			appendCursor(item.Item.ItemContent.CursorType, item.Item.ItemContent.Value)
+		}
+	}
+	return cursors
+}
@@ -0,0 +1,58 @@
+package tracker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// allSnapshots returns every recorded snapshot ordered by tweet ID, then time.
+func (t *Tracker) allSnapshots() []Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := make([]string, 0, len(t.snapshots))
+	for id := range t.snapshots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var all []Snapshot
+	for _, id := range ids {
+		all = append(all, t.snapshots[id]...)
+	}
+	return all
+}
+
+// WriteCSV writes every recorded snapshot as CSV with a header row, one row
+// per snapshot, ordered by tweet ID then observation time.
+func (t *Tracker) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"tweet_id", "observed_at", "likes", "retweets", "replies", "views"}); err != nil {
+		return err
+	}
+	for _, s := range t.allSnapshots() {
+		if err := cw.Write([]string{
+			s.TweetID,
+			s.ObservedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(s.Likes),
+			strconv.Itoa(s.Retweets),
+			strconv.Itoa(s.Replies),
+			strconv.Itoa(s.Views),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the series as a JSON object keyed by tweet ID.
+func (t *Tracker) WriteJSON(w io.Writer) error {
+	series := make(map[string][]Snapshot)
+	for _, s := range t.allSnapshots() {
+		series[s.TweetID] = append(series[s.TweetID], s)
+	}
+	return json.NewEncoder(w).Encode(series)
+}
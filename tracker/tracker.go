@@ -0,0 +1,250 @@
+// Package tracker records how the engagement counters of a set of tweets
+// evolve over time.
+//
+// A Tracker re-fetches every registered tweet on a schedule, in batches, and
+// appends a Snapshot of its likes, retweets, replies and views to the tweet
+// series. Tweets stop being tracked once they are older than the configured
+// window. Series can be exported as CSV or JSON for charting.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/sirupsen/logrus"
+)
+
+// Default configuration values.
+const (
+	DefaultInterval         = 15 * time.Minute
+	DefaultBatchSize        = 20
+	DefaultBatchDelay       = 5 * time.Second
+	DefaultRateLimitBackoff = 15 * time.Minute
+)
+
+// ErrRateLimited is returned by Poll when Twitter rate limited the session.
+// The remaining tweets are fetched on the next round.
+var ErrRateLimited = errors.New("tracker: rate limited")
+
+// TweetFetcher fetches a single tweet. *twitterscraper.Scraper implements it.
+type TweetFetcher interface {
+	GetTweet(id string) (*twitterscraper.Tweet, error)
+}
+
+// Config of a Tracker. Zero values select the defaults.
+type Config struct {
+	// Interval between polling rounds in Run.
+	Interval time.Duration
+	// BatchSize is the number of tweets fetched back to back.
+	BatchSize int
+	// BatchDelay is the pause between two batches.
+	BatchDelay time.Duration
+	// Window after the tweet creation time during which a tweet is tracked.
+	// Zero tracks tweets until they are untracked.
+	Window time.Duration
+	// RateLimitBackoff is the pause before the next round after the session
+	// was rate limited.
+	RateLimitBackoff time.Duration
+}
+
+// Snapshot of the counters of a tweet.
+type Snapshot struct {
+	TweetID    string    `json:"tweet_id"`
+	ObservedAt time.Time `json:"observed_at"`
+	Likes      int       `json:"likes"`
+	Retweets   int       `json:"retweets"`
+	Replies    int       `json:"replies"`
+	Views      int       `json:"views"`
+}
+
+type tracked struct {
+	registeredAt time.Time
+	createdAt    time.Time
+}
+
+// Tracker of tweet engagement. It is safe for concurrent use.
+type Tracker struct {
+	fetcher TweetFetcher
+	config  Config
+
+	mu        sync.Mutex
+	tweets    map[string]*tracked
+	snapshots map[string][]Snapshot
+}
+
+// New creates a Tracker fetching tweets with fetcher.
+func New(fetcher TweetFetcher, config Config) *Tracker {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.BatchDelay < 0 {
+		config.BatchDelay = 0
+	} else if config.BatchDelay == 0 {
+		config.BatchDelay = DefaultBatchDelay
+	}
+	if config.RateLimitBackoff <= 0 {
+		config.RateLimitBackoff = DefaultRateLimitBackoff
+	}
+	return &Tracker{
+		fetcher:   fetcher,
+		config:    config,
+		tweets:    make(map[string]*tracked),
+		snapshots: make(map[string][]Snapshot),
+	}
+}
+
+// Track registers tweet IDs. Already tracked IDs are ignored.
+func (t *Tracker) Track(ids ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for _, id := range ids {
+		if _, ok := t.tweets[id]; !ok {
+			t.tweets[id] = &tracked{registeredAt: now}
+		}
+	}
+}
+
+// Untrack stops tracking tweet IDs. Their recorded series are kept.
+func (t *Tracker) Untrack(ids ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range ids {
+		delete(t.tweets, id)
+	}
+}
+
+// Tracked returns the sorted IDs of the tweets currently tracked.
+func (t *Tracker) Tracked() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := make([]string, 0, len(t.tweets))
+	for id := range t.tweets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Series returns the snapshots recorded for a tweet, oldest first.
+func (t *Tracker) Series(id string) []Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Snapshot(nil), t.snapshots[id]...)
+}
+
+// Run polls the tracked tweets every Interval until ctx is done.
+func (t *Tracker) Run(ctx context.Context) error {
+	for {
+		wait := t.config.Interval
+		if err := t.Poll(ctx); err != nil {
+			if err != ErrRateLimited {
+				return err
+			}
+			logrus.WithField("backoff", t.config.RateLimitBackoff).Warn("Tracker rate limited")
+			wait = t.config.RateLimitBackoff
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Poll runs a single round: tweets older than the window are untracked, then
+// every tracked tweet is fetched in batches and a snapshot is recorded.
+// Tweets that fail to load are logged and retried on the next round.
+func (t *Tracker) Poll(ctx context.Context) error {
+	ids := t.expire(time.Now())
+	for start := 0; start < len(ids); start += t.config.BatchSize {
+		if start > 0 {
+			if err := sleep(ctx, t.config.BatchDelay); err != nil {
+				return err
+			}
+		}
+		end := start + t.config.BatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		for _, id := range ids[start:end] {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			tweet, err := t.fetcher.GetTweet(id)
+			if err != nil {
+				if isRateLimited(err) {
+					return ErrRateLimited
+				}
+				logrus.WithError(err).WithField("tweet_id", id).Warn("Tracker failed to fetch tweet")
+				continue
+			}
+			t.record(id, tweet, time.Now())
+		}
+	}
+	return nil
+}
+
+// expire untracks tweets older than the window and returns the remaining IDs.
+func (t *Tracker) expire(now time.Time) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := make([]string, 0, len(t.tweets))
+	for id, tw := range t.tweets {
+		if t.config.Window > 0 {
+			since := tw.createdAt
+			if since.IsZero() {
+				since = tw.registeredAt
+			}
+			if now.Sub(since) > t.config.Window {
+				delete(t.tweets, id)
+				continue
+			}
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (t *Tracker) record(id string, tweet *twitterscraper.Tweet, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tw, ok := t.tweets[id]; ok && tw.createdAt.IsZero() {
+		tw.createdAt = tweet.TimeParsed
+	}
+	t.snapshots[id] = append(t.snapshots[id], Snapshot{
+		TweetID:    id,
+		ObservedAt: now,
+		Likes:      tweet.Likes,
+		Retweets:   tweet.Retweets,
+		Replies:    tweet.Replies,
+		Views:      tweet.Views,
+	})
+}
+
+// isRateLimited reports whether err is a 429 response from RequestAPI.
+func isRateLimited(err error) bool {
+	var statusErr *twitterscraper.StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
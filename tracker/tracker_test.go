@@ -0,0 +1,122 @@
+package tracker_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twitterscraper "github.com/masa-finance/masa-twitter-scraper"
+	"github.com/masa-finance/masa-twitter-scraper/tracker"
+)
+
+type fakeFetcher struct {
+	mu     sync.Mutex
+	tweets map[string]*twitterscraper.Tweet
+	errs   map[string]error
+	calls  []string
+}
+
+func (f *fakeFetcher) GetTweet(id string) (*twitterscraper.Tweet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, id)
+	if err := f.errs[id]; err != nil {
+		return nil, err
+	}
+	tweet := *f.tweets[id]
+	f.tweets[id].Likes++
+	return &tweet, nil
+}
+
+func TestPollRecordsSnapshots(t *testing.T) {
+	fetcher := &fakeFetcher{tweets: map[string]*twitterscraper.Tweet{
+		"1": {ID: "1", Likes: 10, Views: 100, TimeParsed: time.Now()},
+		"2": {ID: "2", Retweets: 3, TimeParsed: time.Now()},
+	}}
+	tr := tracker.New(fetcher, tracker.Config{BatchSize: 1, BatchDelay: -1})
+	tr.Track("1", "2")
+	for i := 0; i < 2; i++ {
+		if err := tr.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	series := tr.Series("1")
+	if len(series) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d", len(series))
+	}
+	if series[0].Likes != 10 || series[1].Likes != 11 || series[1].Views != 100 {
+		t.Errorf("Unexpected series %+v", series)
+	}
+	if got := tr.Series("2"); len(got) != 2 || got[0].Retweets != 3 {
+		t.Errorf("Unexpected series %+v", got)
+	}
+
+	var csvBuf bytes.Buffer
+	if err := tr.WriteCSV(&csvBuf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvBuf.String()), "\n")
+	if len(lines) != 5 || lines[0] != "tweet_id,observed_at,likes,retweets,replies,views" {
+		t.Errorf("Unexpected CSV %q", csvBuf.String())
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := tr.WriteJSON(&jsonBuf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string][]tracker.Snapshot
+	if err := json.Unmarshal(jsonBuf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded["1"]) != 2 || decoded["1"][1].Likes != 11 {
+		t.Errorf("Unexpected JSON %s", jsonBuf.String())
+	}
+}
+
+func TestPollAgesOutTweets(t *testing.T) {
+	fetcher := &fakeFetcher{tweets: map[string]*twitterscraper.Tweet{
+		"old": {ID: "old", TimeParsed: time.Now().Add(-2 * time.Hour)},
+		"new": {ID: "new", TimeParsed: time.Now()},
+	}}
+	tr := tracker.New(fetcher, tracker.Config{Window: time.Hour, BatchDelay: -1})
+	tr.Track("old", "new")
+	if err := tr.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.Tracked(); len(got) != 1 || got[0] != "new" {
+		t.Errorf("Expected only new tracked, got %v", got)
+	}
+	if got := tr.Series("old"); len(got) != 1 {
+		t.Errorf("Expected aged out series kept, got %d snapshots", len(got))
+	}
+}
+
+func TestPollStopsWhenRateLimited(t *testing.T) {
+	fetcher := &fakeFetcher{
+		tweets: map[string]*twitterscraper.Tweet{"1": {ID: "1"}, "2": {ID: "2"}, "3": {ID: "3"}},
+		errs: map[string]error{
+			"1": errors.New("tweet with ID 1429 not found"),
+			"2": fmt.Errorf("tweet 2: %w", &twitterscraper.StatusError{StatusCode: 429, Status: "429 Too Many Requests"}),
+		},
+	}
+	tr := tracker.New(fetcher, tracker.Config{BatchDelay: -1})
+	tr.Track("1", "2", "3")
+	if err := tr.Poll(context.Background()); err != tracker.ErrRateLimited {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+	if len(fetcher.calls) != 2 {
+		t.Errorf("Expected 2 fetches before stopping, got %v", fetcher.calls)
+	}
+	if got := tr.Tracked(); len(got) != 3 {
+		t.Errorf("Expected failed tweets still tracked, got %v", got)
+	}
+}
@@ -137,74 +137,89 @@ func (s *Scraper) GetTweet(id string) (*Tweet, error) {
 			}
 		}
 	} else {
-		req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/VWFGPVAGkZMGRKGe3GFFnA/TweetDetail")
+		conversation, err := s.fetchTweetDetail(id, "")
 		if err != nil {
 			return nil, err
 		}
 
-		variables := map[string]interface{}{
-			"focalTweetId":                           id,
-			"with_rux_injections":                    false,
-			"includePromotedContent":                 true,
-			"withCommunity":                          true,
-			"withQuickPromoteEligibilityTweetFields": true,
-			"withBirdwatchNotes":                     true,
-			"withVoice":                              true,
-			"withV2Timeline":                         true,
+		tweets := conversation.parse()
+		for _, tweet := range tweets {
+			if tweet.ID == id {
+				return tweet, nil
+			}
 		}
+	}
+	return nil, fmt.Errorf("tweet with ID %s not found", id)
+}
 
-		features := map[string]interface{}{
-			"rweb_lists_timeline_redesign_enabled":                                    true,
-			"responsive_web_graphql_exclude_directive_enabled":                        true,
-			"verified_phone_label_enabled":                                            false,
-			"creator_subscriptions_tweet_preview_api_enabled":                         true,
-			"responsive_web_graphql_timeline_navigation_enabled":                      true,
-			"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
-			"tweetypie_unmention_optimization_enabled":                                true,
-			"responsive_web_edit_tweet_api_enabled":                                   true,
-			"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
-			"view_counts_everywhere_api_enabled":                                      true,
-			"longform_notetweets_consumption_enabled":                                 true,
-			"tweet_awards_web_tipping_enabled":                                        false,
-			"freedom_of_speech_not_reach_fetch_enabled":                               true,
-			"standardized_nudges_misinfo":                                             true,
-			"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": false,
-			"longform_notetweets_rich_text_read_enabled":                              true,
-			"longform_notetweets_inline_media_enabled":                                true,
-			"responsive_web_enhance_cards_enabled":                                    false,
-		}
+// fetchTweetDetail loads a page of the TweetDetail conversation of a tweet.
+// cursor selects the page; an empty cursor loads the focal tweet with its
+// ancestors and the first replies.
+func (s *Scraper) fetchTweetDetail(id string, cursor string) (*threadedConversation, error) {
+	req, err := s.newRequest("GET", "https://twitter.com/i/api/graphql/VWFGPVAGkZMGRKGe3GFFnA/TweetDetail")
+	if err != nil {
+		return nil, err
+	}
 
-		query := url.Values{}
-		query.Set("variables", mapToJSONString(variables))
-		query.Set("features", mapToJSONString(features))
-		req.URL.RawQuery = query.Encode()
+	variables := map[string]interface{}{
+		"focalTweetId":                           id,
+		"with_rux_injections":                    false,
+		"includePromotedContent":                 true,
+		"withCommunity":                          true,
+		"withQuickPromoteEligibilityTweetFields": true,
+		"withBirdwatchNotes":                     true,
+		"withVoice":                              true,
+		"withV2Timeline":                         true,
+	}
+	if cursor != "" {
+		variables["cursor"] = cursor
+		variables["referrer"] = "tweet"
+	}
+
+	features := map[string]interface{}{
+		"rweb_lists_timeline_redesign_enabled":                                    true,
+		"responsive_web_graphql_exclude_directive_enabled":                        true,
+		"verified_phone_label_enabled":                                            false,
+		"creator_subscriptions_tweet_preview_api_enabled":                         true,
+		"responsive_web_graphql_timeline_navigation_enabled":                      true,
+		"responsive_web_graphql_skip_user_profile_image_extensions_enabled":       false,
+		"tweetypie_unmention_optimization_enabled":                                true,
+		"responsive_web_edit_tweet_api_enabled":                                   true,
+		"graphql_is_translatable_rweb_tweet_is_translatable_enabled":              true,
+		"view_counts_everywhere_api_enabled":                                      true,
+		"longform_notetweets_consumption_enabled":                                 true,
+		"tweet_awards_web_tipping_enabled":                                        false,
+		"freedom_of_speech_not_reach_fetch_enabled":                               true,
+		"standardized_nudges_misinfo":                                             true,
+		"tweet_with_visibility_results_prefer_gql_limited_actions_policy_enabled": false,
+		"longform_notetweets_rich_text_read_enabled":                              true,
+		"longform_notetweets_inline_media_enabled":                                true,
+		"responsive_web_enhance_cards_enabled":                                    false,
+	}
 
-		var conversation threadedConversation
+	query := url.Values{}
+	query.Set("variables", mapToJSONString(variables))
+	query.Set("features", mapToJSONString(features))
+	req.URL.RawQuery = query.Encode()
 
-		// Surprisingly, if bearerToken2 is not set, then animated GIFs are not
-		// present in the response for tweets with a GIF + a photo like this one:
-		// https://twitter.com/Twitter/status/1580661436132757506
-		curBearerToken := s.bearerToken
-		if curBearerToken != bearerToken2 {
-			s.setBearerToken(bearerToken2)
-		}
+	var conversation threadedConversation
 
-		err = s.RequestAPI(req, &conversation)
+	// Surprisingly, if bearerToken2 is not set, then animated GIFs are not
+	// present in the response for tweets with a GIF + a photo like this one:
+	// https://twitter.com/Twitter/status/1580661436132757506
+	curBearerToken := s.bearerToken
+	if curBearerToken != bearerToken2 {
+		s.setBearerToken(bearerToken2)
+	}
 
-		if curBearerToken != bearerToken2 {
-			s.setBearerToken(curBearerToken)
-		}
+	err = s.RequestAPI(req, &conversation)
 
-		if err != nil {
-			return nil, err
-		}
+	if curBearerToken != bearerToken2 {
+		s.setBearerToken(curBearerToken)
+	}
 
-		tweets := conversation.parse()
-		for _, tweet := range tweets {
-			if tweet.ID == id {
-				return tweet, nil
-			}
-		}
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("tweet with ID %s not found", id)
+	return &conversation, nil
 }
@@ -14,6 +14,17 @@ var cmpOptions = cmp.Options{
 	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Likes"),
 	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Replies"),
 	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Retweets"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Card"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Poll"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "EditHistoryIDs"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "EditableUntil"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "EditsRemaining"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Bookmarks", "Quotes", "Favorited", "Retweeted"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "DisplayTextRange", "Lang", "Source", "PossiblySensitive", "ReplySettings"),
+	cmpopts.IgnoreFields(twitterscraper.Tweet{}, "Cashtags", "URLEntities"),
+	cmpopts.IgnoreFields(twitterscraper.Photo{}, "AltText", "Width", "Height", "FocusRects", "Availability"),
+	cmpopts.IgnoreFields(twitterscraper.Video{}, "AltText", "Width", "Height", "AspectRatio", "Duration", "Views", "HLSURL", "Availability", "Variants"),
+	cmpopts.IgnoreFields(twitterscraper.GIF{}, "AltText", "Width", "Height", "AspectRatio", "Availability", "Variants"),
 }
 
 func TestGetTweets(t *testing.T) {
@@ -1,71 +1,244 @@
 package twitterscraper
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type (
 	// Mention type.
 	Mention struct {
-		ID       string
-		Username string
-		Name     string
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	}
+
+	// FocusRect is a region of a photo to keep when it is cropped to an
+	// aspect ratio.
+	FocusRect struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	}
+
+	// Variant is an encoding of a video or GIF. Width and Height are known
+	// for MP4 variants only, and Bitrate is 0 for HLS playlists and GIFs.
+	Variant struct {
+		Bitrate     int    `json:"bitrate,omitempty"`
+		ContentType string `json:"content_type"`
+		URL         string `json:"url"`
+		Width       int    `json:"width,omitempty"`
+		Height      int    `json:"height,omitempty"`
 	}
 
 	// Photo type.
 	Photo struct {
-		ID  string
-		URL string
+		ID         string      `json:"id"`
+		URL        string      `json:"url"`
+		AltText    string      `json:"alt_text,omitempty"`
+		Width      int         `json:"width,omitempty"`
+		Height     int         `json:"height,omitempty"`
+		FocusRects []FocusRect `json:"focus_rects,omitempty"`
+		// Availability is "Available" or the reason the photo can't be
+		// shown, like "Unavailable". Empty when unknown.
+		Availability string `json:"availability,omitempty"`
 	}
 
-	// Video type.
+	// Video type. URL is the MP4 variant with the highest bitrate.
 	Video struct {
-		ID      string
-		Preview string
-		URL     string
+		ID          string `json:"id"`
+		Preview     string `json:"preview"`
+		URL         string `json:"url"`
+		AltText     string `json:"alt_text,omitempty"`
+		Width       int    `json:"width,omitempty"`
+		Height      int    `json:"height,omitempty"`
+		AspectRatio []int  `json:"aspect_ratio,omitempty"`
+		// Duration is encoded in JSON as "duration_ms", in milliseconds.
+		Duration     time.Duration `json:"-"`
+		Views        int           `json:"views,omitempty"`
+		HLSURL       string        `json:"hls_url,omitempty"`
+		Availability string        `json:"availability,omitempty"`
+		Variants     []Variant     `json:"variants,omitempty"`
 	}
 
-	// GIF type.
+	// GIF type. URL is the MP4 variant with the highest bitrate.
 	GIF struct {
-		ID      string
-		Preview string
-		URL     string
+		ID           string    `json:"id"`
+		Preview      string    `json:"preview"`
+		URL          string    `json:"url"`
+		AltText      string    `json:"alt_text,omitempty"`
+		Width        int       `json:"width,omitempty"`
+		Height       int       `json:"height,omitempty"`
+		AspectRatio  []int     `json:"aspect_ratio,omitempty"`
+		Availability string    `json:"availability,omitempty"`
+		Variants     []Variant `json:"variants,omitempty"`
+	}
+
+	// Entity is a part of the text of a tweet that Twitter links: a hashtag,
+	// a cashtag, a mention, a URL or the link to the media of the tweet.
+	Entity struct {
+		Type EntityType `json:"type"`
+		// Start and End are the offsets of the entity in Tweet.Text, in
+		// Unicode code points, End excluded.
+		Start int `json:"start"`
+		End   int `json:"end"`
+		// Text is the hashtag or cashtag without its symbol, the screen
+		// name of a mention, or the t.co URL of a link or media.
+		Text        string `json:"text"`
+		UserID      string `json:"user_id,omitempty"`
+		ExpandedURL string `json:"expanded_url,omitempty"`
+		DisplayURL  string `json:"display_url,omitempty"`
+		// MediaURL is the image of a media entity.
+		MediaURL string `json:"media_url,omitempty"`
+	}
+
+	// URLEntity is a link of a tweet.
+	URLEntity struct {
+		// URL is the t.co link in the text.
+		URL         string `json:"url"`
+		ExpandedURL string `json:"expanded_url,omitempty"`
+		DisplayURL  string `json:"display_url,omitempty"`
+		// UnwoundURL is the final URL after redirects, with the title and
+		// description of the page, when Twitter or a URLResolver provided
+		// them.
+		UnwoundURL  string `json:"unwound_url,omitempty"`
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// PollChoice is an option of a poll.
+	PollChoice struct {
+		Label string `json:"label"`
+		Count int    `json:"count"`
+	}
+
+	// Poll attached to a tweet.
+	Poll struct {
+		Choices         []PollChoice `json:"choices"`
+		DurationMinutes int          `json:"duration_minutes,omitempty"`
+		EndTime         time.Time    `json:"end_time"`
+		// Finished is set once voting is closed and the counts are final.
+		Finished bool `json:"finished"`
+	}
+
+	// Card is the preview attached to a tweet: a link summary, a player or an
+	// app card. Name is the card type, like "summary", "summary_large_image",
+	// "player" or "poll2choice_text_only".
+	Card struct {
+		Name         string `json:"name"`
+		URL          string `json:"url,omitempty"`
+		Title        string `json:"title,omitempty"`
+		Description  string `json:"description,omitempty"`
+		Domain       string `json:"domain,omitempty"`
+		Thumbnail    string `json:"thumbnail,omitempty"`
+		PlayerURL    string `json:"player_url,omitempty"`
+		PlayerWidth  int    `json:"player_width,omitempty"`
+		PlayerHeight int    `json:"player_height,omitempty"`
+		// Values holds every string value of the card by key, including
+		// the ones of app cards, like "app_name" or "iphone_app_id".
+		Values map[string]string `json:"values,omitempty"`
 	}
 
-	// Tweet type.
+	// CommunityNote is a Community Notes (formerly Birdwatch) note adding
+	// context to a tweet.
+	CommunityNote struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+		URL  string `json:"url"`
+		// Status is the rating status of the note, like
+		// "CurrentlyRatedHelpful", "CurrentlyRatedNotHelpful" or
+		// "NeedsMoreRatings".
+		Status string `json:"status,omitempty"`
+		// Classification is "MisinformedOrPotentiallyMisleading" or
+		// "NotMisleading". It is only known for notes from GetCommunityNotes.
+		Classification string `json:"classification,omitempty"`
+		// Sources are the links cited by the note.
+		Sources []string   `json:"sources,omitempty"`
+		Created *time.Time `json:"created,omitempty"`
+	}
+
+	// Tweet type. See MarshalJSON for its JSON schema.
 	Tweet struct {
-		ConversationID    string
-		GIFs              []GIF
-		Hashtags          []string
-		HTML              string
-		ID                string
-		InReplyToStatus   *Tweet
-		InReplyToStatusID string
-		IsQuoted          bool
-		IsPin             bool
-		IsReply           bool
-		IsRetweet         bool
-		IsSelfThread      bool
-		Likes             int
-		Name              string
-		Mentions          []Mention
-		PermanentURL      string
-		Photos            []Photo
-		Place             *Place
-		QuotedStatus      *Tweet
-		QuotedStatusID    string
-		Replies           int
-		Retweets          int
-		RetweetedStatus   *Tweet
-		RetweetedStatusID string
-		Text              string
-		Thread            []*Tweet
-		TimeParsed        time.Time
-		Timestamp         int64
-		URLs              []string
-		UserID            string
-		Username          string
-		Videos            []Video
-		Views             int
-		SensitiveContent  bool
+		Bookmarks         int            `json:"bookmarks"`
+		Card              *Card          `json:"card,omitempty"`
+		Cashtags          []string       `json:"cashtags,omitempty"`
+		CommunityNote     *CommunityNote `json:"community_note,omitempty"`
+		ConversationID    string         `json:"conversation_id,omitempty"`
+		DisplayTextRange  []int          `json:"display_text_range,omitempty"`
+		EditableUntil     *time.Time     `json:"editable_until,omitempty"`
+		EditHistoryIDs    []string       `json:"edit_history_ids,omitempty"`
+		EditsRemaining    int            `json:"edits_remaining,omitempty"`
+		Entities          []Entity       `json:"entities,omitempty"`
+		Favorited         bool           `json:"favorited"`
+		GIFs              []GIF          `json:"gifs,omitempty"`
+		Hashtags          []string       `json:"hashtags,omitempty"`
+		HTML              string         `json:"html,omitempty"`
+		ID                string         `json:"id"`
+		InReplyToStatus   *Tweet         `json:"-"`
+		InReplyToStatusID string         `json:"in_reply_to_status_id,omitempty"`
+		IsEdited          bool           `json:"is_edited"`
+		IsPin             bool           `json:"is_pin"`
+		IsQuoted          bool           `json:"is_quoted"`
+		IsReply           bool           `json:"is_reply"`
+		IsRetweet         bool           `json:"is_retweet"`
+		IsSelfThread      bool           `json:"is_self_thread"`
+		Lang              string         `json:"lang,omitempty"`
+		Likes             int            `json:"likes"`
+		Mentions          []Mention      `json:"mentions,omitempty"`
+		Name              string         `json:"name"`
+		PermanentURL      string         `json:"permanent_url"`
+		Photos            []Photo        `json:"photos,omitempty"`
+		Place             *Place         `json:"place,omitempty"`
+		Poll              *Poll          `json:"poll,omitempty"`
+		PossiblySensitive bool           `json:"possibly_sensitive"`
+		QuotedStatus      *Tweet         `json:"-"`
+		QuotedStatusID    string         `json:"quoted_status_id,omitempty"`
+		Quotes            int            `json:"quotes"`
+		Replies           int            `json:"replies"`
+		// ReplySettings is who can reply to the tweet: empty for everyone,
+		// or the conversation control policy, like "Community" (people
+		// followed by the author) or "ByInvitation" (people mentioned).
+		ReplySettings     string `json:"reply_settings,omitempty"`
+		Retweeted         bool   `json:"retweeted"`
+		RetweetedStatus   *Tweet `json:"-"`
+		RetweetedStatusID string `json:"retweeted_status_id,omitempty"`
+		Retweets          int    `json:"retweets"`
+		SensitiveContent  bool   `json:"sensitive_content"`
+		// Source is the name of the client the tweet was posted with.
+		Source              string      `json:"source,omitempty"`
+		Text                string      `json:"text"`
+		Thread              []*Tweet    `json:"-"`
+		TimeParsed          time.Time   `json:"-"`
+		Timestamp           int64       `json:"timestamp"`
+		URLEntities         []URLEntity `json:"url_entities,omitempty"`
+		URLs                []string    `json:"urls,omitempty"`
+		UserID              string      `json:"user_id"`
+		Username            string      `json:"username"`
+		Videos              []Video     `json:"videos,omitempty"`
+		Views               int         `json:"views"`
+		WithheldInCountries []string    `json:"withheld_in_countries,omitempty"`
+	}
+
+	// List of twitter users.
+	List struct {
+		ID              string     `json:"id"`
+		Name            string     `json:"name"`
+		Description     string     `json:"description,omitempty"`
+		IsPrivate       bool       `json:"is_private"`
+		MemberCount     int        `json:"member_count"`
+		SubscriberCount int        `json:"subscriber_count"`
+		Banner          string     `json:"banner,omitempty"`
+		Created         *time.Time `json:"created,omitempty"`
+		OwnerID         string     `json:"owner_id"`
+		OwnerUsername   string     `json:"owner_username"`
+		URL             string     `json:"url"`
+	}
+
+	// ListResult of scrapping.
+	ListResult struct {
+		List
+		Error error
 	}
 
 	// ProfileResult of scrapping.
@@ -81,53 +254,66 @@ type (
 	}
 
 	legacyTweet struct {
-		ConversationIDStr string `json:"conversation_id_str"`
-		CreatedAt         string `json:"created_at"`
-		FavoriteCount     int    `json:"favorite_count"`
-		FullText          string `json:"full_text"`
+		BookmarkCount       int        `json:"bookmark_count"`
+		Card                legacyCard `json:"card"`
+		ConversationControl struct {
+			Policy string `json:"policy"`
+		} `json:"conversation_control"`
+		ConversationIDStr string      `json:"conversation_id_str"`
+		CreatedAt         string      `json:"created_at"`
+		DisplayTextRange  []int       `json:"display_text_range"`
+		EditControl       editControl `json:"ext_edit_control"`
+		FavoriteCount     int         `json:"favorite_count"`
+		Favorited         bool        `json:"favorited"`
+		FullText          string      `json:"full_text"`
 		Entities          struct {
 			Hashtags []struct {
-				Text string `json:"text"`
+				Indices []int  `json:"indices"`
+				Text    string `json:"text"`
 			} `json:"hashtags"`
 			Media []struct {
+				DisplayURL    string `json:"display_url"`
+				ExpandedURL   string `json:"expanded_url"`
+				Indices       []int  `json:"indices"`
 				MediaURLHttps string `json:"media_url_https"`
 				Type          string `json:"type"`
 				URL           string `json:"url"`
 			} `json:"media"`
+			Symbols []struct {
+				Indices []int  `json:"indices"`
+				Text    string `json:"text"`
+			} `json:"symbols"`
 			URLs []struct {
+				DisplayURL  string `json:"display_url"`
 				ExpandedURL string `json:"expanded_url"`
+				Indices     []int  `json:"indices"`
 				URL         string `json:"url"`
+				Unwound     struct {
+					URL         string `json:"url"`
+					Title       string `json:"title"`
+					Description string `json:"description"`
+				} `json:"unwound"`
 			} `json:"urls"`
 			UserMentions []struct {
 				IDStr      string `json:"id_str"`
+				Indices    []int  `json:"indices"`
 				Name       string `json:"name"`
 				ScreenName string `json:"screen_name"`
 			} `json:"user_mentions"`
 		} `json:"entities"`
 		ExtendedEntities struct {
-			Media []struct {
-				IDStr                    string `json:"id_str"`
-				MediaURLHttps            string `json:"media_url_https"`
-				ExtSensitiveMediaWarning struct {
-					AdultContent    bool `json:"adult_content"`
-					GraphicViolence bool `json:"graphic_violence"`
-					Other           bool `json:"other"`
-				} `json:"ext_sensitive_media_warning"`
-				Type      string `json:"type"`
-				URL       string `json:"url"`
-				VideoInfo struct {
-					Variants []struct {
-						Bitrate int    `json:"bitrate"`
-						URL     string `json:"url"`
-					} `json:"variants"`
-				} `json:"video_info"`
-			} `json:"media"`
+			Media []legacyMedia `json:"media"`
 		} `json:"extended_entities"`
 		IDStr                 string `json:"id_str"`
 		InReplyToStatusIDStr  string `json:"in_reply_to_status_id_str"`
+		IsQuoteStatus         bool   `json:"is_quote_status"`
+		Lang                  string `json:"lang"`
 		Place                 Place  `json:"place"`
+		PossiblySensitive     bool   `json:"possibly_sensitive"`
+		QuoteCount            int    `json:"quote_count"`
 		ReplyCount            int    `json:"reply_count"`
 		RetweetCount          int    `json:"retweet_count"`
+		Retweeted             bool   `json:"retweeted"`
 		RetweetedStatusIDStr  string `json:"retweeted_status_id_str"`
 		RetweetedStatusResult struct {
 			Result *result `json:"result"`
@@ -136,30 +322,91 @@ type (
 		SelfThread        struct {
 			IDStr string `json:"id_str"`
 		} `json:"self_thread"`
+		Source    string    `json:"source"`
 		Time      time.Time `json:"time"`
 		UserIDStr string    `json:"user_id_str"`
 		Views     struct {
 			State string `json:"state"`
 			Count string `json:"count"`
 		} `json:"ext_views"`
+		WithheldInCountries []string `json:"withheld_in_countries"`
+	}
+
+	legacyMedia struct {
+		IDStr                    string `json:"id_str"`
+		MediaURLHttps            string `json:"media_url_https"`
+		ExtAltText               string `json:"ext_alt_text"`
+		ExtSensitiveMediaWarning struct {
+			AdultContent    bool `json:"adult_content"`
+			GraphicViolence bool `json:"graphic_violence"`
+			Other           bool `json:"other"`
+		} `json:"ext_sensitive_media_warning"`
+		ExtMediaAvailability struct {
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		} `json:"ext_media_availability"`
+		// MediaStats is set by the GraphQL API, Ext.MediaStats by the
+		// legacy API. Both are decoded lazily since their shape varies:
+		// counts may be numbers or strings, and a missing legacy count is
+		// the string "Missing".
+		MediaStats struct {
+			ViewCount json.RawMessage `json:"viewCount"`
+		} `json:"mediaStats"`
+		Ext struct {
+			MediaStats struct {
+				R json.RawMessage `json:"r"`
+			} `json:"mediaStats"`
+		} `json:"ext"`
+		OriginalInfo struct {
+			Width      int         `json:"width"`
+			Height     int         `json:"height"`
+			FocusRects []FocusRect `json:"focus_rects"`
+		} `json:"original_info"`
+		Type      string `json:"type"`
+		URL       string `json:"url"`
+		VideoInfo struct {
+			AspectRatio    []int `json:"aspect_ratio"`
+			DurationMillis int   `json:"duration_millis"`
+			Variants       []struct {
+				Bitrate     int    `json:"bitrate"`
+				ContentType string `json:"content_type"`
+				URL         string `json:"url"`
+			} `json:"variants"`
+		} `json:"video_info"`
 	}
 
 	legacyUser struct {
-		CreatedAt   string `json:"created_at"`
-		Description string `json:"description"`
-		Entities    struct {
+		CanDM               bool   `json:"can_dm"`
+		CreatedAt           string `json:"created_at"`
+		DefaultProfileImage bool   `json:"default_profile_image"`
+		Description         string `json:"description"`
+		Entities            struct {
+			Description struct {
+				Urls []struct {
+					DisplayURL  string `json:"display_url"`
+					ExpandedURL string `json:"expanded_url"`
+					URL         string `json:"url"`
+				} `json:"urls"`
+			} `json:"description"`
 			URL struct {
 				Urls []struct {
 					ExpandedURL string `json:"expanded_url"`
 				} `json:"urls"`
 			} `json:"url"`
 		} `json:"entities"`
+		// ExtIsBlueVerified is set by the v1 API, IsBlueVerified by the
+		// GraphQL API outside of legacy.
+		ExtIsBlueVerified    bool     `json:"ext_is_blue_verified"`
+		FastFollowersCount   int      `json:"fast_followers_count"`
 		FavouritesCount      int      `json:"favourites_count"`
 		FollowersCount       int      `json:"followers_count"`
 		FriendsCount         int      `json:"friends_count"`
 		IDStr                string   `json:"id_str"`
+		IsBlueVerified       bool     `json:"is_blue_verified"`
 		ListedCount          int      `json:"listed_count"`
+		MediaCount           int      `json:"media_count"`
 		Name                 string   `json:"name"`
+		NormalFollowersCount int      `json:"normal_followers_count"`
 		Location             string   `json:"location"`
 		PinnedTweetIdsStr    []string `json:"pinned_tweet_ids_str"`
 		ProfileBannerURL     string   `json:"profile_banner_url"`
@@ -168,6 +415,8 @@ type (
 		ScreenName           string   `json:"screen_name"`
 		StatusesCount        int      `json:"statuses_count"`
 		Verified             bool     `json:"verified"`
+		VerifiedType         string   `json:"verified_type"`
+		WithheldInCountries  []string `json:"withheld_in_countries"`
 	}
 
 	Place struct {
@@ -0,0 +1,147 @@
+package twitterscraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// URLResolver resolves a short link, like a t.co link, to the URL it
+// redirects to.
+type URLResolver interface {
+	ResolveURL(ctx context.Context, rawURL string) (string, error)
+}
+
+// MapResolver resolves links from a map of short link to URL, without any
+// request. Links missing from the map resolve to themselves.
+type MapResolver map[string]string
+
+// ResolveURL implements URLResolver.
+func (m MapResolver) ResolveURL(_ context.Context, rawURL string) (string, error) {
+	if resolved, ok := m[rawURL]; ok {
+		return resolved, nil
+	}
+	return rawURL, nil
+}
+
+// DefaultMaxRedirects is the number of redirects RedirectResolver follows
+// when MaxRedirects is not set.
+const DefaultMaxRedirects = 10
+
+// RedirectResolver resolves links by following their HTTP redirects with
+// HEAD requests.
+type RedirectResolver struct {
+	// Client sends the requests, for instance Scraper.HTTPClient to go
+	// through its proxy. Defaults to http.DefaultClient.
+	Client       *http.Client
+	MaxRedirects int
+}
+
+// ResolveURL implements URLResolver.
+func (r *RedirectResolver) ResolveURL(ctx context.Context, rawURL string) (string, error) {
+	client := http.DefaultClient
+	if r.Client != nil {
+		client = r.Client
+	}
+	// Follow redirects one by one, so the client doesn't load the final
+	// page.
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	maxRedirects := r.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+
+	current := rawURL
+	for i := 0; i < maxRedirects; i++ {
+		resp, err := redirectRequest(ctx, &noRedirect, "HEAD", current)
+		if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+			resp, err = redirectRequest(ctx, &noRedirect, "GET", current)
+		}
+		if err != nil {
+			return "", err
+		}
+		location := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 || location == "" {
+			return current, nil
+		}
+		next, err := resp.Request.URL.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		current = next.String()
+	}
+	return "", fmt.Errorf("%s: stopped after %d redirects", rawURL, maxRedirects)
+}
+
+// redirectRequest sends a request without reading the response, which is
+// closed.
+func redirectRequest(ctx context.Context, client *http.Client, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// ResolveURLs resolves the links of the tweet that Twitter didn't expand,
+// or only expanded to another t.co link, and the ones without an unwound
+// URL when unwind is set. The expanded URLs, entities and HTML of the tweet
+// are updated. It stops at the first error.
+func (tweet *Tweet) ResolveURLs(ctx context.Context, resolver URLResolver, unwind bool) error {
+	changed := false
+	for i := range tweet.URLEntities {
+		u := &tweet.URLEntities[i]
+		expanded := u.ExpandedURL != "" && u.ExpandedURL != u.URL && !isTwitterShortLink(u.ExpandedURL)
+		if expanded && (!unwind || u.UnwoundURL != "") {
+			continue
+		}
+		source := u.ExpandedURL
+		if source == "" {
+			source = u.URL
+		}
+		resolved, err := resolver.ResolveURL(ctx, source)
+		if err != nil {
+			return err
+		}
+		if resolved == "" || resolved == source {
+			continue
+		}
+		if !expanded {
+			u.ExpandedURL = resolved
+		}
+		u.UnwoundURL = resolved
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	byURL := make(map[string]string, len(tweet.URLEntities))
+	urls := make([]string, 0, len(tweet.URLEntities))
+	for _, u := range tweet.URLEntities {
+		byURL[u.URL] = u.ExpandedURL
+		urls = append(urls, u.ExpandedURL)
+	}
+	tweet.URLs = urls
+	for i := range tweet.Entities {
+		if entity := &tweet.Entities[i]; entity.Type == EntityURL && byURL[entity.Text] != "" {
+			entity.ExpandedURL = byURL[entity.Text]
+		}
+	}
+	tweet.HTML = tweet.RenderHTML()
+	return nil
+}
+
+func isTwitterShortLink(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Host == "t.co"
+}
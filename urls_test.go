@@ -0,0 +1,120 @@
+package twitterscraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const urlsLegacyJSON = `{
+	"id_str": "1", "user_id_str": "10", "full_text": "$TSLA and $AAPL https://t.co/a https://t.co/b",
+	"entities": {
+		"symbols": [{"text": "TSLA", "indices": [0, 5]}, {"text": "AAPL", "indices": [10, 15]}],
+		"urls": [
+			{"url": "https://t.co/a", "expanded_url": "https://example.com/a", "display_url": "example.com/a", "indices": [16, 30],
+			 "unwound": {"url": "https://example.com/final", "title": "Final", "description": "The final page"}},
+			{"url": "https://t.co/b", "indices": [31, 45]}
+		]
+	}
+}`
+
+func TestParseCashtagsAndURLEntities(t *testing.T) {
+	var timeline timelineV1
+	err := json.Unmarshal([]byte(`{"globalObjects": {"tweets": {"1": `+urlsLegacyJSON+`}, "users": {"10": {"screen_name": "alice"}}}}`), &timeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res result
+	if err := json.Unmarshal([]byte(`{"__typename": "Tweet", "legacy": `+urlsLegacyJSON+`}`), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []URLEntity{
+		{
+			URL:         "https://t.co/a",
+			ExpandedURL: "https://example.com/a",
+			DisplayURL:  "example.com/a",
+			UnwoundURL:  "https://example.com/final",
+			Title:       "Final",
+			Description: "The final page",
+		},
+		{URL: "https://t.co/b"},
+	}
+	for name, tweet := range map[string]*Tweet{"v1": timeline.parseTweet("1"), "v2": res.parse()} {
+		if !reflect.DeepEqual(tweet.Cashtags, []string{"TSLA", "AAPL"}) {
+			t.Errorf("%s: unexpected cashtags %v", name, tweet.Cashtags)
+		}
+		if !reflect.DeepEqual(tweet.URLEntities, want) {
+			t.Errorf("%s: unexpected URL entities %+v", name, tweet.URLEntities)
+		}
+	}
+}
+
+func TestResolveURLs(t *testing.T) {
+	var res result
+	if err := json.Unmarshal([]byte(`{"__typename": "Tweet", "legacy": `+urlsLegacyJSON+`}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	tweet := res.parse()
+	resolver := MapResolver{
+		"https://t.co/a": "https://example.com/ignored",
+		"https://t.co/b": "https://example.com/b",
+	}
+	if err := tweet.ResolveURLs(context.Background(), resolver, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := tweet.URLEntities[0]; got.ExpandedURL != "https://example.com/a" {
+		t.Errorf("Expected expanded link to be kept, got %+v", got)
+	}
+	if got := tweet.URLEntities[1]; got.ExpandedURL != "https://example.com/b" || got.UnwoundURL != "https://example.com/b" {
+		t.Errorf("Expected resolved link, got %+v", got)
+	}
+	if !reflect.DeepEqual(tweet.URLs, []string{"https://example.com/a", "https://example.com/b"}) {
+		t.Errorf("Unexpected URLs %v", tweet.URLs)
+	}
+	if !strings.Contains(tweet.HTML, `<a href="https://example.com/b">https://t.co/b</a>`) {
+		t.Errorf("Expected HTML to link the resolved URL, got %s", tweet.HTML)
+	}
+}
+
+func TestRedirectResolver(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/short":
+			http.Redirect(w, r, "/middle", http.StatusMovedPermanently)
+		case "/middle":
+			if r.Method == "HEAD" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.Write([]byte("final page"))
+		}
+	}))
+	defer srv.Close()
+
+	resolver := &RedirectResolver{Client: srv.Client(), MaxRedirects: 3}
+	resolved, err := resolver.ResolveURL(context.Background(), srv.URL+"/short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != srv.URL+"/final" {
+		t.Errorf("Expected %s/final, got %s", srv.URL, resolved)
+	}
+	want := []string{"HEAD /short", "HEAD /middle", "GET /middle", "HEAD /final"}
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("Expected requests %v, got %v", want, methods)
+	}
+	if _, err := resolver.ResolveURL(context.Background(), srv.URL+"/loop"); err == nil {
+		t.Error("Expected an error for a redirect loop")
+	}
+}
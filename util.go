@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -13,10 +14,8 @@ import (
 )
 
 var (
-	reHashtag    = regexp.MustCompile(`\B(\#\S+\b)`)
-	reTwitterURL = regexp.MustCompile(`https:(\/\/t\.co\/([A-Za-z0-9]|[A-Za-z]){10})`)
-	reUsername   = regexp.MustCompile(`\B(\@\S{1,15}\b)`)
-	twURL        = urlParse("https://twitter.com")
+	reHTMLTag = regexp.MustCompile(`<[^>]*>`)
+	twURL     = urlParse("https://twitter.com")
 )
 
 func (s *Scraper) newRequest(method string, url string) (*http.Request, error) {
@@ -152,6 +151,41 @@ func getTweetTimeline(ctx context.Context, query string, maxTweetsNbr int, fetch
 	return channel
 }
 
+// parseTweetMetadata sets the fields of a tweet that both APIs decode the
+// same way from the legacy tweet object.
+func parseTweetMetadata(tw *Tweet, tweet *legacyTweet) {
+	tw.Bookmarks = tweet.BookmarkCount
+	tw.DisplayTextRange = tweet.DisplayTextRange
+	tw.Favorited = tweet.Favorited
+	tw.IsQuoted = tw.IsQuoted || tweet.IsQuoteStatus
+	tw.Lang = tweet.Lang
+	tw.PossiblySensitive = tweet.PossiblySensitive
+	tw.Quotes = tweet.QuoteCount
+	tw.ReplySettings = tweet.ConversationControl.Policy
+	tw.Retweeted = tweet.Retweeted
+	tw.Source = parseSource(tweet.Source)
+	tw.WithheldInCountries = tweet.WithheldInCountries
+	for _, symbol := range tweet.Entities.Symbols {
+		tw.Cashtags = append(tw.Cashtags, symbol.Text)
+	}
+	for _, u := range tweet.Entities.URLs {
+		tw.URLEntities = append(tw.URLEntities, URLEntity{
+			URL:         u.URL,
+			ExpandedURL: u.ExpandedURL,
+			DisplayURL:  u.DisplayURL,
+			UnwoundURL:  u.Unwound.URL,
+			Title:       u.Unwound.Title,
+			Description: u.Unwound.Description,
+		})
+	}
+}
+
+// parseSource returns the client name of a source, given as a link like
+// `<a href="https://mobile.twitter.com" rel="nofollow">Twitter Web App</a>`.
+func parseSource(source string) string {
+	return html.UnescapeString(strings.TrimSpace(reHTMLTag.ReplaceAllString(source, "")))
+}
+
 func parseLegacyTweet(user *legacyUser, tweet *legacyTweet) *Tweet {
 	tweetID := tweet.IDStr
 	if tweetID == "" {
@@ -186,6 +220,7 @@ func parseLegacyTweet(user *legacyUser, tweet *legacyTweet) *Tweet {
 		tw.IsQuoted = true
 		tw.QuotedStatusID = tweet.QuotedStatusIDStr
 	}
+	parseTweetMetadata(tw, tweet)
 	if tweet.InReplyToStatusIDStr != "" {
 		tw.IsReply = true
 		tw.InReplyToStatusID = tweet.InReplyToStatusIDStr
@@ -194,8 +229,10 @@ func parseLegacyTweet(user *legacyUser, tweet *legacyTweet) *Tweet {
 		tw.IsRetweet = true
 		tw.RetweetedStatusID = tweet.RetweetedStatusIDStr
 		if tweet.RetweetedStatusResult.Result != nil {
-			tw.RetweetedStatus = parseLegacyTweet(&tweet.RetweetedStatusResult.Result.Core.UserResults.Result.Legacy, &tweet.RetweetedStatusResult.Result.Legacy)
-			tw.RetweetedStatusID = tw.RetweetedStatus.ID
+			if retweeted := tweet.RetweetedStatusResult.Result.parse(); retweeted != nil {
+				tw.RetweetedStatus = retweeted
+				tw.RetweetedStatusID = retweeted.ID
+			}
 		}
 	}
 
@@ -226,148 +263,17 @@ func parseLegacyTweet(user *legacyUser, tweet *legacyTweet) *Tweet {
 		})
 	}
 
-	for _, media := range tweet.ExtendedEntities.Media {
-		if media.Type == "photo" {
-			photo := Photo{
-				ID:  media.IDStr,
-				URL: media.MediaURLHttps,
-			}
-
-			tw.Photos = append(tw.Photos, photo)
-		} else if media.Type == "video" {
-			video := Video{
-				ID:      media.IDStr,
-				Preview: media.MediaURLHttps,
-			}
-
-			maxBitrate := 0
-			for _, variant := range media.VideoInfo.Variants {
-				if variant.Bitrate > maxBitrate {
-					video.URL = strings.TrimSuffix(variant.URL, "?tag=10")
-					maxBitrate = variant.Bitrate
-				}
-			}
-
-			tw.Videos = append(tw.Videos, video)
-		} else if media.Type == "animated_gif" {
-			gif := GIF{
-				ID:      media.IDStr,
-				Preview: media.MediaURLHttps,
-			}
-
-			// Twitter's API doesn't provide bitrate for GIFs, (it's always set to zero).
-			// Therefore we check for `>=` instead of `>` in the loop below.
-			// Also, GIFs have just a single variant today. Just in case that changes in the future,
-			// and there will be multiple variants, we'll pick the one with the highest bitrate,
-			// if other one will have a non-zero bitrate.
-			maxBitrate := 0
-			for _, variant := range media.VideoInfo.Variants {
-				if variant.Bitrate >= maxBitrate {
-					gif.URL = variant.URL
-					maxBitrate = variant.Bitrate
-				}
-			}
-
-			tw.GIFs = append(tw.GIFs, gif)
-		}
-
-		if !tw.SensitiveContent {
-			sensitive := media.ExtSensitiveMediaWarning
-			tw.SensitiveContent = sensitive.AdultContent || sensitive.GraphicViolence || sensitive.Other
-		}
-	}
+	parseMedia(tw, tweet.ExtendedEntities.Media)
 
 	for _, url := range tweet.Entities.URLs {
 		tw.URLs = append(tw.URLs, url.ExpandedURL)
 	}
 
-	tw.HTML = tweet.FullText
-	tw.HTML = reHashtag.ReplaceAllStringFunc(tw.HTML, func(hashtag string) string {
-		return fmt.Sprintf(`<a href="https://twitter.com/hashtag/%s">%s</a>`,
-			strings.TrimPrefix(hashtag, "#"),
-			hashtag,
-		)
-	})
-	tw.HTML = reUsername.ReplaceAllStringFunc(tw.HTML, func(username string) string {
-		return fmt.Sprintf(`<a href="https://twitter.com/%s">%s</a>`,
-			strings.TrimPrefix(username, "@"),
-			username,
-		)
-	})
-	var foundedMedia []string
-	tw.HTML = reTwitterURL.ReplaceAllStringFunc(tw.HTML, func(tco string) string {
-		for _, entity := range tweet.Entities.URLs {
-			if tco == entity.URL {
-				return fmt.Sprintf(`<a href="%s">%s</a>`, entity.ExpandedURL, tco)
-			}
-		}
-		for _, entity := range tweet.ExtendedEntities.Media {
-			if tco == entity.URL {
-				foundedMedia = append(foundedMedia, entity.MediaURLHttps)
-				return fmt.Sprintf(`<br><a href="%s"><img src="%s"/></a>`, tco, entity.MediaURLHttps)
-			}
-		}
-		return tco
-	})
-	for _, photo := range tw.Photos {
-		url := photo.URL
-		if stringInSlice(url, foundedMedia) {
-			continue
-		}
-		tw.HTML += fmt.Sprintf(`<br><img src="%s"/>`, url)
-	}
-	for _, video := range tw.Videos {
-		url := video.Preview
-		if stringInSlice(url, foundedMedia) {
-			continue
-		}
-		tw.HTML += fmt.Sprintf(`<br><img src="%s"/>`, url)
-	}
-	for _, gif := range tw.GIFs {
-		url := gif.Preview
-		if stringInSlice(url, foundedMedia) {
-			continue
-		}
-		tw.HTML += fmt.Sprintf(`<br><img src="%s"/>`, url)
-	}
-	tw.HTML = strings.Replace(tw.HTML, "\n", "<br>", -1)
+	tw.Entities = parseEntities(tw.Text, tweet)
+	tw.HTML = tw.RenderHTML()
 	return tw
 }
 
-func parseProfile(user legacyUser) Profile {
-	profile := Profile{
-		Avatar:         user.ProfileImageURLHTTPS,
-		Banner:         user.ProfileBannerURL,
-		Biography:      user.Description,
-		FollowersCount: user.FollowersCount,
-		FollowingCount: user.FavouritesCount,
-		FriendsCount:   user.FriendsCount,
-		IsPrivate:      user.Protected,
-		IsVerified:     user.Verified,
-		LikesCount:     user.FavouritesCount,
-		ListedCount:    user.ListedCount,
-		Location:       user.Location,
-		Name:           user.Name,
-		PinnedTweetIDs: user.PinnedTweetIdsStr,
-		TweetsCount:    user.StatusesCount,
-		URL:            "https://twitter.com/" + user.ScreenName,
-		UserID:         user.IDStr,
-		Username:       user.ScreenName,
-	}
-
-	tm, err := time.Parse(time.RubyDate, user.CreatedAt)
-	if err == nil {
-		tm = tm.UTC()
-		profile.Joined = &tm
-	}
-
-	if len(user.Entities.URL.Urls) > 0 {
-		profile.Website = user.Entities.URL.Urls[0].ExpandedURL
-	}
-
-	return profile
-}
-
 func mapToJSONString(data map[string]interface{}) string {
 	jsonBytes, err := json.Marshal(data)
 	if err != nil {